@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"reflect"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// WaitForClusterRoleToAggregate polls aggregateRole until the
+// ClusterRoleAggregationController has merged expectedRule into its Rules,
+// which happens asynchronously after a contributing role matching its
+// AggregationRule label selectors is created or changed.
+func WaitForClusterRoleToAggregate(c v1beta1rbac.ClusterRolesGetter, aggregateRole string, expectedRule rbacv1beta1.PolicyRule) error {
+	var lastSeen []rbacv1beta1.PolicyRule
+	err := wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		role, err := c.ClusterRoles().Get(aggregateRole, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		lastSeen = role.Rules
+		for _, rule := range role.Rules {
+			if reflect.DeepEqual(rule, expectedRule) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for clusterrole/%s to aggregate rule %+v: %v; rules currently on the role: %+v", aggregateRole, expectedRule, err, lastSeen)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+	restclient "k8s.io/client-go/rest"
+)
+
+// AnonymousUser and AnonymousGroup are the well-known identity the apiserver
+// attributes to requests carrying no credentials at all.
+const (
+	AnonymousUser  = "system:anonymous"
+	AnonymousGroup = "system:unauthenticated"
+)
+
+// NewAnonymousClient returns a clientset built from base with all credentials
+// stripped, so requests it makes are authenticated (if at all) purely by the
+// apiserver's anonymous authenticator as system:anonymous/system:unauthenticated.
+func NewAnonymousClient(base *restclient.Config) (clientset.Interface, error) {
+	defer traceOp("NewAnonymousClient")()
+
+	config := restclient.AnonymousClientConfig(base)
+	config.UserAgent = IdentityUserAgent(AnonymousUser)
+	return clientset.NewForConfig(config)
+}
+
+// WaitForAnonymousAuthorizationUpdate checks whether system:anonymous
+// (in the system:unauthenticated group) can perform the given verb and
+// action, retrying until it matches allowed or policyCachePollTimeout elapses.
+func WaitForAnonymousAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, verb, namespace string, resource schema.GroupResource, allowed bool) error {
+	review := &authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Group:     resource.Group,
+				Verb:      verb,
+				Resource:  resource.Resource,
+				Namespace: namespace,
+			},
+			User:   AnonymousUser,
+			Groups: []string{AnonymousGroup},
+		},
+	}
+
+	return wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		response, err := c.SubjectAccessReviews().Create(review)
+		if err != nil {
+			return false, err
+		}
+		return response.Status.Allowed == allowed, nil
+	})
+}
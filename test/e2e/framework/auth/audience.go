@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// RequestServiceAccountToken mints a token for the named ServiceAccount
+// scoped to audiences via TokenRequest.
+func RequestServiceAccountToken(c clientset.Interface, namespace, name string, audiences []string) (string, error) {
+	tr, err := c.CoreV1().ServiceAccounts(namespace).CreateToken(name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences: audiences,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("requesting token for serviceaccount %s/%s with audiences %v: %v", namespace, name, audiences, err)
+	}
+	return tr.Status.Token, nil
+}
+
+// AssertTokenAudienceRejected verifies, through both TokenReview and a real
+// API call, that the apiserver rejects a token whose audience does not match
+// the audience the caller expects to authenticate with (wantAudience). This
+// is the negative side of audience-bound tokens: a token minted for one
+// audience must not authenticate requests aimed at another.
+func AssertTokenAudienceRejected(adminClient clientset.Interface, base *restclient.Config, token, wantAudience string) error {
+	review, err := adminClient.AuthenticationV1().TokenReviews().Create(&authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{wantAudience},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("running TokenReview: %v", err)
+	}
+	if review.Status.Authenticated {
+		return fmt.Errorf("TokenReview unexpectedly authenticated a token not minted for audience %q", wantAudience)
+	}
+
+	config := restclient.AnonymousClientConfig(base)
+	config.BearerToken = token
+	tokenClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("building client for audience-mismatched token: %v", err)
+	}
+	_, err = tokenClient.CoreV1().Namespaces().List(metav1.ListOptions{})
+	if err == nil {
+		return fmt.Errorf("expected request with audience-mismatched token to be unauthenticated, but it succeeded")
+	}
+	if !apierrors.IsUnauthorized(err) {
+		return fmt.Errorf("expected 401 Unauthorized for audience-mismatched token, got: %v", err)
+	}
+	return nil
+}
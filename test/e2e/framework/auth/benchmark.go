@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// LatencyPercentiles summarizes a set of SubjectAccessReview round-trip
+// latencies. Values are inclusive of the create call only, not surrounding
+// test bookkeeping.
+type LatencyPercentiles struct {
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// MeasureSubjectAccessReviewLatency issues n SubjectAccessReviews for the
+// given spec back-to-back and returns latency percentiles, so e2e
+// performance suites can track authorizer (and webhook authorizer cache)
+// latency regressions across releases.
+func MeasureSubjectAccessReviewLatency(c v1beta1authorization.SubjectAccessReviewsGetter, spec authorizationv1beta1.SubjectAccessReviewSpec, n int) (LatencyPercentiles, error) {
+	if n <= 0 {
+		return LatencyPercentiles{}, fmt.Errorf("n must be positive, got %d", n)
+	}
+
+	durations := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		review := &authorizationv1beta1.SubjectAccessReview{Spec: spec}
+		start := time.Now()
+		if _, err := c.SubjectAccessReviews().Create(review); err != nil {
+			return LatencyPercentiles{}, fmt.Errorf("SubjectAccessReview %d/%d failed: %v", i+1, n, err)
+		}
+		durations = append(durations, time.Since(start))
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return LatencyPercentiles{
+		Count: len(durations),
+		P50:   percentile(durations, 0.50),
+		P90:   percentile(durations, 0.90),
+		P99:   percentile(durations, 0.99),
+		Max:   durations[len(durations)-1],
+	}, nil
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
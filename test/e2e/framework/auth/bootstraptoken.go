@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
+)
+
+// BootstrapTokenOptions configures the bootstrap token Secret created by
+// CreateBootstrapToken.
+type BootstrapTokenOptions struct {
+	// Usages defaults to just "authentication" if left empty.
+	Usages      []string
+	ExtraGroups []string
+	TTL         time.Duration
+}
+
+// CreateBootstrapToken creates a bootstrap token Secret in kube-system with a
+// randomly generated ID and secret, applying the given usages/extra-groups/
+// TTL, and returns the bearer token string ("<id>.<secret>") plus a cleanup
+// function that deletes the Secret.
+func CreateBootstrapToken(c clientset.Interface, opts BootstrapTokenOptions) (token string, cleanup func(), err error) {
+	token, err = bootstraputil.GenerateBootstrapToken()
+	if err != nil {
+		return "", nil, err
+	}
+	tokenID, tokenSecret := splitBootstrapToken(token)
+
+	usages := opts.Usages
+	if len(usages) == 0 {
+		usages = []string{"authentication"}
+	}
+
+	data := map[string][]byte{
+		bootstrapapi.BootstrapTokenIDKey:     []byte(tokenID),
+		bootstrapapi.BootstrapTokenSecretKey: []byte(tokenSecret),
+	}
+	for _, usage := range usages {
+		data[bootstrapapi.BootstrapTokenUsagePrefix+usage] = []byte("true")
+	}
+	if len(opts.ExtraGroups) > 0 {
+		data[bootstrapapi.BootstrapTokenExtraGroupsKey] = []byte(strings.Join(opts.ExtraGroups, ","))
+	}
+	if opts.TTL > 0 {
+		data[bootstrapapi.BootstrapTokenExpirationKey] = []byte(time.Now().Add(opts.TTL).Format(time.RFC3339))
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      bootstraputil.BootstrapTokenSecretName(tokenID),
+		},
+		Type: bootstrapapi.SecretTypeBootstrapToken,
+		Data: data,
+	}
+	if _, err := c.CoreV1().Secrets(metav1.NamespaceSystem).Create(secret); err != nil {
+		return "", nil, fmt.Errorf("creating bootstrap token secret: %v", err)
+	}
+
+	cleanup = func() {
+		if err := c.CoreV1().Secrets(metav1.NamespaceSystem).Delete(secret.Name, nil); err != nil {
+			logf("WARNING: failed to clean up bootstrap token secret/%s: %v", secret.Name, err)
+		}
+	}
+	return token, cleanup, nil
+}
+
+// BootstrapTokenUsername returns the username the apiserver's bootstrap
+// token authenticator attributes requests bearing token to.
+func BootstrapTokenUsername(token string) string {
+	tokenID, _ := splitBootstrapToken(token)
+	return bootstrapapi.BootstrapUserPrefix + tokenID
+}
+
+// NewBootstrapTokenClient returns a clientset that authenticates using the
+// given bootstrap bearer token, exercising the same authenticator path a
+// kubeadm join uses.
+func NewBootstrapTokenClient(base *restclient.Config, token string) (clientset.Interface, error) {
+	config := restclient.AnonymousClientConfig(base)
+	config.BearerToken = token
+	config.UserAgent = IdentityUserAgent(BootstrapTokenUsername(token))
+	return clientset.NewForConfig(config)
+}
+
+func splitBootstrapToken(token string) (id, secret string) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return token, ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+)
+
+// BoundResult reports what a *WithResult binding helper actually did, so a
+// test that requires a binding to have really happened can assert on it
+// instead of silently tolerating the plain Bind* functions' (nil, nil)
+// RBAC-disabled no-op.
+type BoundResult int
+
+const (
+	// Created means the binding was submitted to the API server.
+	Created BoundResult = iota
+	// SkippedRBACDisabled means IsRBACEnabled reported RBAC is not enabled,
+	// so no binding was attempted.
+	SkippedRBACDisabled
+	// Failed means a binding was attempted but the underlying Bind* call
+	// returned an error, so nothing was created.
+	Failed
+)
+
+func (r BoundResult) String() string {
+	switch r {
+	case Created:
+		return "Created"
+	case SkippedRBACDisabled:
+		return "SkippedRBACDisabled"
+	case Failed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// BindClusterRoleWithResult behaves like BindClusterRole, but also reports
+// whether the binding was actually created or skipped because RBAC is
+// disabled, instead of relying on callers to infer that from a nil
+// ClusterRoleBinding.
+func BindClusterRoleWithResult(c bindingsGetter, clusterRole, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.ClusterRoleBinding, BoundResult, error) {
+	if !IsRBACEnabled(c) {
+		return nil, SkippedRBACDisabled, nil
+	}
+	binding, err := BindClusterRole(c, clusterRole, ns, subjects...)
+	if err != nil {
+		return nil, Failed, err
+	}
+	return binding, Created, nil
+}
+
+// BindClusterRoleInNamespaceWithResult behaves like BindClusterRoleInNamespace,
+// but also reports whether the binding was actually created or skipped
+// because RBAC is disabled.
+func BindClusterRoleInNamespaceWithResult(c bindingsGetter, clusterRole, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, BoundResult, error) {
+	if !IsRBACEnabled(c) {
+		return nil, SkippedRBACDisabled, nil
+	}
+	binding, err := BindClusterRoleInNamespace(c, clusterRole, ns, subjects...)
+	if err != nil {
+		return nil, Failed, err
+	}
+	return binding, Created, nil
+}
+
+// BindRoleInNamespaceWithResult behaves like BindRoleInNamespace, but also
+// reports whether the binding was actually created or skipped because RBAC
+// is disabled.
+func BindRoleInNamespaceWithResult(c bindingsGetter, role, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, BoundResult, error) {
+	if !IsRBACEnabled(c) {
+		return nil, SkippedRBACDisabled, nil
+	}
+	binding, err := BindRoleInNamespace(c, role, ns, subjects...)
+	if err != nil {
+		return nil, Failed, err
+	}
+	return binding, Created, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/time/rate"
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BulkBindingTemplate describes the ClusterRoleBindings CreateBindingsBulk
+// creates. NameFormat is a Printf-style template taking the binding's index,
+// e.g. "scale-test-%d".
+type BulkBindingTemplate struct {
+	NameFormat  string
+	ClusterRole string
+	Subjects    []rbacv1beta1.Subject
+}
+
+// CreateBindingsBulk creates count ClusterRoleBindings from template, with at
+// most concurrency creations in flight and no more than qps issued per
+// second (qps <= 0 means unlimited), for scale suites measuring authorizer
+// performance against thousands of policies without hammering the apiserver
+// harder than a real rollout would. It returns every binding successfully
+// created, in no particular order, along with a best-effort cleanup func
+// that deletes them all, and stops issuing new creations at the first error.
+func CreateBindingsBulk(c bindingsGetter, count, concurrency int, qps float64, template BulkBindingTemplate) ([]*rbacv1beta1.ClusterRoleBinding, func(), error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if qps > 0 {
+		limiter = rate.NewLimiter(rate.Limit(qps), 1)
+	}
+
+	var (
+		mu       sync.Mutex
+		created  []*rbacv1beta1.ClusterRoleBinding
+		firstErr error
+	)
+
+	ctx := context.Background()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < count; i++ {
+		mu.Lock()
+		stop := firstErr != nil
+		mu.Unlock()
+		if stop {
+			break
+		}
+
+		if err := limiter.Wait(ctx); err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			mu.Unlock()
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			binding, err := c.ClusterRoleBindings().Create(&rbacv1beta1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fmt.Sprintf(template.NameFormat, index),
+					Annotations: traceAnnotations(),
+				},
+				RoleRef: rbacv1beta1.RoleRef{
+					APIGroup: rbacAPIGroup,
+					Kind:     "ClusterRole",
+					Name:     template.ClusterRole,
+				},
+				Subjects: template.Subjects,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("creating binding %d/%d: %v", index, count, err)
+				}
+				return
+			}
+			created = append(created, binding)
+			recordObjectCreated()
+		}(i)
+	}
+	wg.Wait()
+
+	cleanup := func() {
+		for _, binding := range created {
+			if err := c.ClusterRoleBindings().Delete(binding.Name, nil); err != nil {
+				logf("WARNING: failed to clean up clusterrolebinding/%s: %v", binding.Name, err)
+				continue
+			}
+			recordObjectCleaned()
+		}
+	}
+
+	return created, cleanup, firstErr
+}
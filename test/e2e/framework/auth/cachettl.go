@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import "time"
+
+// Authorizer cache TTLs the target cluster is configured with, e.g. the
+// webhook authorizer's --authorization-webhook-cache-authorized-ttl and
+// --authorization-webhook-cache-unauthorized-ttl. They default to zero,
+// meaning waits use only policyCachePollTimeout.
+var (
+	authorizedDecisionCacheTTL   time.Duration
+	unauthorizedDecisionCacheTTL time.Duration
+)
+
+// SetAuthorizerCacheTTLs records the cluster's authorizer cache TTLs so that
+// subsequent revocation waits (expecting allowed to become false) extend
+// their deadline to at least authorizedTTL, and grant waits (expecting
+// allowed to become true) extend to at least unauthorizedTTL, instead of
+// failing spuriously before a cached decision could possibly have expired.
+func SetAuthorizerCacheTTLs(authorizedTTL, unauthorizedTTL time.Duration) {
+	authorizedDecisionCacheTTL = authorizedTTL
+	unauthorizedDecisionCacheTTL = unauthorizedTTL
+}
+
+// cacheAwarePollTimeout returns the deadline a wait for expectedAllowed
+// should use: whichever is larger of policyCachePollTimeout and the TTL of
+// the decision that may currently be cached and stale.
+func cacheAwarePollTimeout(expectedAllowed bool) time.Duration {
+	timeout := policyCachePollTimeout
+	// A wait expecting "allowed" may be stuck behind a cached "denied"
+	// decision, and vice versa: the TTL that matters is the one for the
+	// decision opposite the one we're waiting to observe.
+	relevantTTL := authorizedDecisionCacheTTL
+	if expectedAllowed {
+		relevantTTL = unauthorizedDecisionCacheTTL
+	}
+	if relevantTTL > timeout {
+		timeout = relevantTTL
+	}
+	return timeout
+}
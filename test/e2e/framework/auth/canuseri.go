@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// CanUserI impersonates user (with groups) through base and issues a
+// SelfSubjectAccessReview for verb/resource, exercising the same
+// impersonate-then-self-review path as `kubectl auth can-i --as`, rather
+// than an admin-issued SubjectAccessReview naming the user. This catches
+// authorizer bugs that only manifest from the impersonated user's own
+// perspective, such as an authorizer that consults extra fields only the
+// impersonation filter populates.
+func CanUserI(base *restclient.Config, user string, groups []string, verb string, resource schema.GroupResource) (bool, error) {
+	config := restclient.CopyConfig(base)
+	config.Impersonate = restclient.ImpersonationConfig{UserName: user, Groups: groups}
+	config.UserAgent = IdentityUserAgent(user)
+
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return false, err
+	}
+
+	review, err := client.AuthorizationV1beta1().SelfSubjectAccessReviews().Create(&authorizationv1beta1.SelfSubjectAccessReview{
+		Spec: authorizationv1beta1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Group:    resource.Group,
+				Verb:     verb,
+				Resource: resource.Resource,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import "sync"
+
+// ProviderCapabilities describes what this package's helpers can rely on
+// for the provider under test, so provider quirks are declared in one place
+// instead of as one-off checks scattered through the helpers (the
+// historical example being the GKE SubjectAccessReview-endpoint-missing
+// handling in WaitForNamedAuthorizationUpdate).
+type ProviderCapabilities struct {
+	// SARAvailable is false on providers that do not expose the
+	// SubjectAccessReview endpoint at all (e.g. some managed GKE clusters).
+	// When false, WaitForNamedAuthorizationUpdate (and the wait helpers
+	// built on it) skips straight to its endpoint-missing fallback instead
+	// of first spending a poll discovering that via a NotFound error.
+	// WaitForSubjectAccessReview, WaitForNamedAuthorizationUpdateWithReason,
+	// WaitForNamedAuthorizationUpdateWithVersion and
+	// WaitForAuthorizationUpdateWithContext do not consult it.
+	SARAvailable bool
+	// CanConfigureWebhookAuthz is true on providers where a test's
+	// credential is allowed to point the apiserver's authorization webhook
+	// at an arbitrary URL for the duration of a test, as AuthorizationWebhook
+	// assumes it can.
+	CanConfigureWebhookAuthz bool
+	// CanReadAuditLogs is true on providers where a test's credential can
+	// read the apiserver's audit log output.
+	CanReadAuditLogs bool
+}
+
+// DefaultProviderCapabilities describes a stock, self-managed cluster: SAR
+// is available, and both webhook authorization and audit log configuration
+// are assumed reachable to callers with cluster-admin credentials.
+var DefaultProviderCapabilities = ProviderCapabilities{
+	SARAvailable:             true,
+	CanConfigureWebhookAuthz: true,
+	CanReadAuditLogs:         true,
+}
+
+var (
+	capabilitiesMu sync.Mutex
+	capabilities   = DefaultProviderCapabilities
+)
+
+// SetProviderCapabilities overrides the ProviderCapabilities this package's
+// helpers consult. A provider-specific test setup (e.g. a GKE suite's
+// BeforeSuite) should call this once, before any helper in this package
+// runs, rather than relying on the helpers to keep guessing provider quirks
+// from API errors.
+func SetProviderCapabilities(c ProviderCapabilities) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	capabilities = c
+}
+
+// Capabilities returns the currently configured ProviderCapabilities.
+func Capabilities() ProviderCapabilities {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	return capabilities
+}
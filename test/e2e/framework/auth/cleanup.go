@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// CleanupBindingsWithPrefix deletes every ClusterRoleBinding whose name
+// starts with prefix, matching the "<ns>--<role>" naming convention BindClusterRole
+// uses. CI jobs that reuse a cluster across runs can call this with a
+// namespace prefix to sweep up bindings left behind by aborted runs.
+func CleanupBindingsWithPrefix(c v1beta1rbac.ClusterRoleBindingsGetter, prefix string) error {
+	bindings, err := c.ClusterRoleBindings().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, binding := range bindings.Items {
+		if !strings.HasPrefix(binding.Name, prefix) {
+			continue
+		}
+		if err := c.ClusterRoleBindings().Delete(binding.Name, nil); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		recordObjectCleaned()
+		logf("cleaned up stale clusterrolebinding/%s", binding.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
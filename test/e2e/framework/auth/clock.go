@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"k8s.io/utils/clock"
+)
+
+// pkgClock is this package's source of Now/Since for the primary
+// WaitForNamedAuthorizationUpdate poll loop, in place of calling time.Now
+// directly, so a caller can substitute a deterministic clock.Clock instead
+// of exercising real wall-clock delays. It complements rather than replaces
+// pollFunc/sleepFunc (helpers.go): those are this package's own internal
+// test seams, while pkgClock/SetClock is the public one.
+//
+// k8s.io/utils/clock/testing's FakeClock is not vendored in this tree, so a
+// caller wanting a fake clock.Clock must currently implement the small
+// interface itself rather than reuse an off-the-shelf one.
+var pkgClock clock.Clock = clock.RealClock{}
+
+// SetClock overrides pkgClock and the sleepFunc the wait helpers use for
+// their 1-second SubjectAccessReview-endpoint-missing fallback sleep,
+// returning a restore func that puts back the previous clock.
+func SetClock(c clock.Clock) (restore func()) {
+	previousClock, previousSleep := pkgClock, sleepFunc
+	pkgClock = c
+	sleepFunc = c.Sleep
+	return func() {
+		pkgClock = previousClock
+		sleepFunc = previousSleep
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForClusterWideAuthorizationUpdate checks whether user can perform verb
+// on resource across all namespaces. This differs from
+// WaitForAuthorizationUpdate(c, user, "", verb, resource, allowed) only in
+// making the cluster-wide intent explicit at call sites, since an empty
+// namespace argument there reads ambiguously next to a namespace-scoped call.
+func WaitForClusterWideAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, user, verb string, resource schema.GroupResource, allowed bool) error {
+	return WaitForAuthorizationUpdate(c, user, "", verb, resource, allowed)
+}
+
+// AssertNamespaceScopedOnly waits for user to be authorized to verb resource
+// in namespace, then asserts the same user is denied the cluster-wide
+// (all-namespaces) form of the same verb/resource -- the common
+// multi-tenancy requirement that a namespace-scoped grant must not leak into
+// a cluster-wide list/watch.
+func AssertNamespaceScopedOnly(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb string, resource schema.GroupResource) error {
+	if err := WaitForAuthorizationUpdate(c, user, namespace, verb, resource, true); err != nil {
+		return fmt.Errorf("expected %q to be allowed to %s %s in namespace %q: %v", user, verb, resource, namespace, err)
+	}
+	if err := WaitForClusterWideAuthorizationUpdate(c, user, verb, resource, false); err != nil {
+		return fmt.Errorf("expected %q to be denied cluster-wide %s %s despite namespace-scoped access to %q: %v", user, verb, resource, namespace, err)
+	}
+	return nil
+}
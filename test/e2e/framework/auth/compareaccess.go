@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// AccessAttribute is one ResourceAttributes to evaluate as part of
+// CompareAccess.
+type AccessAttribute struct {
+	Namespace string
+	Verb      string
+	Group     string
+	Resource  string
+	Name      string
+}
+
+// AccessDiff describes one attribute where two identities' access differed.
+type AccessDiff struct {
+	Attribute   AccessAttribute
+	AllowedForA bool
+	AllowedForB bool
+}
+
+// CompareAccess evaluates every attribute in attrs for both userA and userB
+// and returns the attributes where they differ, for tests asserting that a
+// scoped persona has strictly fewer permissions than an admin persona (or
+// more generally, that two identities' access diverges only where expected).
+// An empty result means the two identities have identical access across attrs.
+func CompareAccess(c v1beta1authorization.SubjectAccessReviewsGetter, userA, userB string, attrs []AccessAttribute) ([]AccessDiff, error) {
+	var diffs []AccessDiff
+	for _, attr := range attrs {
+		allowedA, err := checkAccess(c, userA, attr)
+		if err != nil {
+			return nil, err
+		}
+		allowedB, err := checkAccess(c, userB, attr)
+		if err != nil {
+			return nil, err
+		}
+		if allowedA != allowedB {
+			diffs = append(diffs, AccessDiff{Attribute: attr, AllowedForA: allowedA, AllowedForB: allowedB})
+		}
+	}
+	return diffs, nil
+}
+
+func checkAccess(c v1beta1authorization.SubjectAccessReviewsGetter, user string, attr AccessAttribute) (bool, error) {
+	review, err := c.SubjectAccessReviews().Create(&authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Namespace: attr.Namespace,
+				Verb:      attr.Verb,
+				Group:     attr.Group,
+				Resource:  attr.Resource,
+				Name:      attr.Name,
+			},
+			User: user,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
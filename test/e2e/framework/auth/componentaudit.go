@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// ExpectedComponentRule is one entry of an allowlist of rules a component
+// identity (e.g. "system:kube-scheduler", "system:kube-controller-manager",
+// or a controller service account) is expected to hold.
+type ExpectedComponentRule struct {
+	APIGroups []string
+	Resources []string
+	Verbs     []string
+}
+
+// AuditComponentPermissions impersonates componentUsername and lists its
+// effective permissions via SelfSubjectRulesReview (there is no
+// non-self SubjectRulesReview API), then reports any resource rule whose
+// APIGroups, Resources and Verbs are not exactly equal, element by element
+// and in the same order, to those of some entry in expected -- catching
+// bootstrap policy drift that quietly widens what a control-plane component
+// can do. A rule that is a subset of an expected entry is still reported.
+func AuditComponentPermissions(base *restclient.Config, componentUsername, namespace string, expected []ExpectedComponentRule) ([]authorizationv1beta1.ResourceRule, error) {
+	config := restclient.CopyConfig(base)
+	config.Impersonate = restclient.ImpersonationConfig{UserName: componentUsername}
+	config.UserAgent = IdentityUserAgent(componentUsername)
+
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("building impersonating client for %q: %v", componentUsername, err)
+	}
+
+	review := &authorizationv1beta1.SelfSubjectRulesReview{
+		Spec: authorizationv1beta1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+	result, err := client.AuthorizationV1beta1().SelfSubjectRulesReviews().Create(review)
+	if err != nil {
+		return nil, fmt.Errorf("running SelfSubjectRulesReview as %q: %v", componentUsername, err)
+	}
+
+	var unexpected []authorizationv1beta1.ResourceRule
+	for _, rule := range result.Status.ResourceRules {
+		if !ruleIsAllowlisted(rule, expected) {
+			unexpected = append(unexpected, rule)
+		}
+	}
+	return unexpected, nil
+}
+
+func ruleIsAllowlisted(rule authorizationv1beta1.ResourceRule, expected []ExpectedComponentRule) bool {
+	for _, e := range expected {
+		if stringSlicesEqual(rule.APIGroups, e.APIGroups) &&
+			stringSlicesEqual(rule.Resources, e.Resources) &&
+			stringSlicesEqual(rule.Verbs, e.Verbs) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
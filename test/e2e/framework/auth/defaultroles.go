@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// DefaultBootstrapClusterRoles are the user-facing ClusterRoles the
+// bootstrap policy controller creates on every cluster; see
+// plugin/pkg/auth/authorizer/rbac/bootstrappolicy. Suites that run
+// immediately after cluster bring-up can race this controller, so
+// WaitForDefaultClusterRoles lets them wait it out explicitly instead of
+// failing on a missing role.
+var DefaultBootstrapClusterRoles = []string{
+	"cluster-admin",
+	"admin",
+	"edit",
+	"view",
+	"system:node",
+	"system:node-proxier",
+	"system:kube-scheduler",
+	"system:kube-controller-manager",
+	"system:kube-dns",
+	"system:basic-user",
+	"system:discovery",
+}
+
+// WaitForDefaultClusterRoles waits until every role in
+// DefaultBootstrapClusterRoles exists.
+func WaitForDefaultClusterRoles(c v1beta1rbac.ClusterRolesGetter) error {
+	err := wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		for _, name := range DefaultBootstrapClusterRoles {
+			if _, err := c.ClusterRoles().Get(name, metav1.GetOptions{}); err != nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("default bootstrap ClusterRoles did not all appear within %s: %v", policyCachePollTimeout, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	restclient "k8s.io/client-go/rest"
+
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// AssertDelegatedAuthorization verifies that a real request made through the
+// aggregated API server (aggregatedClient) agrees with a SubjectAccessReview
+// for user on the given resource evaluated by the delegating kube-apiserver
+// (sarClient). This exercises the full delegated authorization path an
+// aggregated API server relies on, rather than trusting the aggregate's own
+// in-process decision. It creates no binding itself: user is checked with
+// whatever bindings already exist when it runs.
+func AssertDelegatedAuthorization(sarClient v1beta1authorization.SubjectAccessReviewsGetter, aggregatedClient restclient.Interface, user string, resource schema.GroupResource, verb, namespace, name string) error {
+	review := &authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Group:     resource.Group,
+				Verb:      verb,
+				Resource:  resource.Resource,
+				Namespace: namespace,
+				Name:      name,
+			},
+			User: user,
+		},
+	}
+	response, err := sarClient.SubjectAccessReviews().Create(review)
+	if err != nil {
+		return fmt.Errorf("delegated authorization: SubjectAccessReview for %q failed: %v", user, err)
+	}
+
+	req := aggregatedClient.Verb(verb).Resource(resource.Resource).Namespace(namespace)
+	if name != "" {
+		req = req.Name(name)
+	}
+	reqErr := req.Do().Error()
+	allowedByAggregate := !apierrors.IsForbidden(reqErr) && !apierrors.IsUnauthorized(reqErr)
+
+	if allowedByAggregate != response.Status.Allowed {
+		return fmt.Errorf("delegated authorization mismatch for %q on %s/%s: SubjectAccessReview allowed=%v, aggregated request allowed=%v (request error: %v)",
+			user, resource.String(), verb, response.Status.Allowed, allowedByAggregate, reqErr)
+	}
+	return nil
+}
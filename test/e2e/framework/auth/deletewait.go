@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForBindingDeleted deletes the named ClusterRoleBinding (if it exists)
+// and waits until a representative SAR for user/verb/resource now returns
+// denied, closing the gap between etcd deletion and authorizer cache
+// invalidation that otherwise makes negative tests flaky.
+func WaitForBindingDeleted(c bindingsGetter, sarClient v1beta1authorization.SubjectAccessReviewsGetter, bindingName, user, verb string, resource schema.GroupResource) error {
+	if err := c.ClusterRoleBindings().Delete(bindingName, nil); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting clusterrolebinding/%s: %v", bindingName, err)
+	}
+	if err := WaitForAuthorizationUpdate(sarClient, user, "", verb, resource, false); err != nil {
+		return fmt.Errorf("waiting for clusterrolebinding/%s deletion to revoke %s/%s from %q: %v", bindingName, verb, resource, user, err)
+	}
+	return nil
+}
+
+// WaitForRoleDeleted deletes the named ClusterRole (if it exists) and waits
+// until a representative SAR for user/verb/resource now returns denied.
+func WaitForRoleDeleted(c bindingsGetter, sarClient v1beta1authorization.SubjectAccessReviewsGetter, roleName, user, verb string, resource schema.GroupResource) error {
+	if err := c.ClusterRoles().Delete(roleName, nil); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting clusterrole/%s: %v", roleName, err)
+	}
+	if err := WaitForAuthorizationUpdate(sarClient, user, "", verb, resource, false); err != nil {
+		return fmt.Errorf("waiting for clusterrole/%s deletion to revoke %s/%s from %q: %v", roleName, verb, resource, user, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// SensitiveAttribute is one (verb, resource) pair to probe as part of
+// AssertDenyByDefault.
+type SensitiveAttribute struct {
+	Verb     string
+	Resource schema.GroupResource
+}
+
+// DefaultSensitiveAttributes is a representative sample of attributes that a
+// brand new identity with no bindings should never be authorized for, used
+// as AssertDenyByDefault's default set.
+func DefaultSensitiveAttributes() []SensitiveAttribute {
+	return []SensitiveAttribute{
+		{Verb: "get", Resource: CoreResource("secrets")},
+		{Verb: "list", Resource: CoreResource("secrets")},
+		{Verb: "proxy", Resource: SubResource("", "nodes", "proxy")},
+		{Verb: "create", Resource: CoreResource("pods")},
+		{Verb: "create", Resource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}},
+		{Verb: "update", Resource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"}},
+		{Verb: "delete", Resource: CoreResource("namespaces")},
+		{Verb: "get", Resource: CoreResource("*")},
+	}
+}
+
+// AssertDenyByDefault asserts that user, presumed to have no bindings yet,
+// is denied every attribute in attrs (DefaultSensitiveAttributes if nil),
+// giving suites a one-call baseline check to run before they start granting
+// permissions, instead of hand-rolling a handful of ad hoc denial checks
+// that tend to miss a sensitive verb/resource combination.
+func AssertDenyByDefault(c v1beta1authorization.SubjectAccessReviewsGetter, user string, attrs []SensitiveAttribute) error {
+	if attrs == nil {
+		attrs = DefaultSensitiveAttributes()
+	}
+	for _, attr := range attrs {
+		if err := WaitForAuthorizationUpdate(c, user, "", attr.Verb, attr.Resource, false); err != nil {
+			return fmt.Errorf("expected %q to be denied by default for %s %s: %v", user, attr.Verb, attr.Resource, err)
+		}
+	}
+	return nil
+}
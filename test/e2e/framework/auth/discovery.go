@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// AssertBasicUserAccess exercises the two access grants every authenticated
+// identity gets regardless of RBAC bindings -- the system:discovery and
+// system:basic-user bootstrap ClusterRoleBindings -- and fails if either is
+// unavailable to client. It's meant to be run against arbitrary identities
+// produced by this package's identity helpers, to catch a binding that was
+// accidentally scoped away from "system:authenticated".
+func AssertBasicUserAccess(client clientset.Interface) error {
+	if _, err := client.Discovery().ServerVersion(); err != nil {
+		return fmt.Errorf("expected discovery access (system:discovery) to succeed: %v", err)
+	}
+	if _, err := client.Discovery().ServerGroups(); err != nil {
+		return fmt.Errorf("expected discovery of API groups (system:discovery) to succeed: %v", err)
+	}
+
+	ssar := &authorizationv1beta1.SelfSubjectAccessReview{
+		Spec: authorizationv1beta1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Verb:     "get",
+				Resource: "namespaces",
+			},
+		},
+	}
+	if _, err := client.AuthorizationV1beta1().SelfSubjectAccessReviews().Create(ssar); err != nil {
+		return fmt.Errorf("expected SelfSubjectAccessReview access (system:basic-user) to succeed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForAuthorizationUpdateDuration behaves like WaitForAuthorizationUpdate
+// but also returns how long the wait took, for perf-sensitive suites
+// asserting an upper bound on authorizer cache convergence instead of only
+// pass/fail. The duration is still meaningful on error: it reflects how long
+// was spent waiting before giving up.
+func WaitForAuthorizationUpdateDuration(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb string, resource schema.GroupResource, allowed bool) (time.Duration, error) {
+	return WaitForNamedAuthorizationUpdateDuration(c, user, namespace, verb, "", resource, allowed)
+}
+
+// WaitForNamedAuthorizationUpdateDuration is the WaitForNamedAuthorizationUpdate
+// counterpart of WaitForAuthorizationUpdateDuration.
+func WaitForNamedAuthorizationUpdateDuration(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (time.Duration, error) {
+	start := time.Now()
+	err := WaitForNamedAuthorizationUpdate(c, user, namespace, verb, resourceName, resource, allowed)
+	return time.Since(start), err
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrantEscalateOnRoles creates a ClusterRole granting the "escalate" verb on
+// the named roleNames (of kind roleKind: "roles" or "clusterroles") and
+// binds it to subject, covering the rarely-tested RBAC escalation escape
+// hatch that lets a subject grant permissions it doesn't itself hold, but
+// only by editing one of the named roles.
+func GrantEscalateOnRoles(c bindingsGetter, name, roleKind string, roleNames []string, subject rbacv1beta1.Subject) error {
+	return grantVerbOnRoles(c, name, "escalate", roleKind, roleNames, subject)
+}
+
+// GrantBindOnRoles creates a ClusterRole granting the "bind" verb on the
+// named roleNames and binds it to subject, allowing the subject to reference
+// those (and only those) roles from a RoleBinding/ClusterRoleBinding it
+// creates without needing the permissions the role itself grants.
+func GrantBindOnRoles(c bindingsGetter, name, roleKind string, roleNames []string, subject rbacv1beta1.Subject) error {
+	return grantVerbOnRoles(c, name, "bind", roleKind, roleNames, subject)
+}
+
+func grantVerbOnRoles(c bindingsGetter, name, verb, roleKind string, roleNames []string, subject rbacv1beta1.Subject) error {
+	role := &rbacv1beta1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: traceAnnotations(),
+		},
+		Rules: []rbacv1beta1.PolicyRule{{
+			Verbs:         []string{verb},
+			APIGroups:     []string{"rbac.authorization.k8s.io"},
+			Resources:     []string{roleKind},
+			ResourceNames: roleNames,
+		}},
+	}
+	if _, err := c.ClusterRoles().Create(role); err != nil {
+		return fmt.Errorf("creating clusterrole/%s granting %q on %s %v: %v", name, verb, roleKind, roleNames, err)
+	}
+
+	if _, err := BindClusterRole(c, name, name, subject); err != nil {
+		return fmt.Errorf("binding clusterrole/%s to %s: %v", name, FormatSubject(subject), err)
+	}
+	return nil
+}
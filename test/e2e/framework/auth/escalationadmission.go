@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// escalationRejectionSubstring is the message fragment the RBAC escalation
+// admission plugin (pkg/registry/rbac/validation.ConfirmNoEscalation) always
+// includes when it rejects a create/update for granting permissions the
+// requester doesn't itself hold. Matching on it lets escalation-prevention
+// specs distinguish this specific failure from any other 403.
+const escalationRejectionSubstring = "attempting to grant RBAC permissions not currently held"
+
+// AssertClusterRoleEscalationRejected asserts that creating the given
+// ClusterRole as c fails specifically because of the RBAC escalation
+// admission check, not merely with some generic Forbidden error.
+func AssertClusterRoleEscalationRejected(c v1beta1rbac.ClusterRolesGetter, role *rbacv1beta1.ClusterRole) error {
+	_, err := c.ClusterRoles().Create(role)
+	return requireEscalationRejection(err)
+}
+
+// AssertClusterRoleBindingEscalationRejected asserts that creating the given
+// ClusterRoleBinding as c fails specifically because of the RBAC "bind"
+// escalation admission check.
+func AssertClusterRoleBindingEscalationRejected(c v1beta1rbac.ClusterRoleBindingsGetter, binding *rbacv1beta1.ClusterRoleBinding) error {
+	_, err := c.ClusterRoleBindings().Create(binding)
+	return requireEscalationRejection(err)
+}
+
+func requireEscalationRejection(err error) error {
+	if err == nil {
+		return fmt.Errorf("expected the RBAC escalation admission check to reject the request, but it succeeded")
+	}
+	if !apierrors.IsForbidden(err) {
+		return fmt.Errorf("expected a Forbidden error from the RBAC escalation admission check, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), escalationRejectionSubstring) {
+		return fmt.Errorf("expected the RBAC escalation admission check's rejection message, got: %v", err)
+	}
+	return nil
+}
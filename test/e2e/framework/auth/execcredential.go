@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NewExecCredentialClient writes a small shell script exec credential plugin
+// to a temp file that always prints token as an ExecCredential, and returns a
+// clientset configured to authenticate via it -- exercising the same
+// client-go exec transport a real credential plugin (e.g. a cloud provider's
+// kubectl auth plugin) uses, instead of setting BearerToken directly.
+//
+// The returned cleanup func removes the temp script and should be deferred
+// by the caller.
+func NewExecCredentialClient(base *restclient.Config, token string) (clientset.Interface, func(), error) {
+	dir, err := ioutil.TempDir("", "auth-exec-credential")
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating exec credential plugin dir: %v", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	script := filepath.Join(dir, "exec-credential-plugin.sh")
+	contents := fmt.Sprintf(`#!/bin/sh
+cat <<'EOF'
+{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":%q}}
+EOF
+`, token)
+	if err := ioutil.WriteFile(script, []byte(contents), 0700); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("writing exec credential plugin: %v", err)
+	}
+
+	config := restclient.AnonymousClientConfig(base)
+	config.BearerToken = ""
+	config.ExecProvider = &clientcmdapi.ExecConfig{
+		Command:    script,
+		APIVersion: "client.authentication.k8s.io/v1beta1",
+	}
+	config.UserAgent = IdentityUserAgent(fmt.Sprintf("exec-credential:%s", token))
+
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return client, cleanup, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// BindClusterRoleFor binds clusterRole at the cluster scope for subjects, and
+// starts a timer that deletes the binding after duration elapses. The
+// returned cleanup func deletes the binding immediately and stops the timer;
+// callers should defer it so a spec that fails or panics before duration
+// elapses does not leave the elevated binding around any longer than the
+// deferred call takes to run. Calling cleanup after the timer has already
+// fired is a harmless no-op (the binding is simply already gone).
+func BindClusterRoleFor(c bindingsGetter, clusterRole, ns string, duration time.Duration, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.ClusterRoleBinding, func(), error) {
+	binding, err := BindClusterRole(c, clusterRole, ns, subjects...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if binding == nil {
+		// RBAC disabled; nothing to bind or expire.
+		return nil, func() {}, nil
+	}
+
+	timer := time.AfterFunc(duration, func() {
+		deleteClusterRoleBinding(c, binding.Name)
+	})
+
+	cleanup := func() {
+		timer.Stop()
+		deleteClusterRoleBinding(c, binding.Name)
+	}
+	return binding, cleanup, nil
+}
+
+func deleteClusterRoleBinding(c bindingsGetter, name string) {
+	if err := c.ClusterRoleBindings().Delete(name, nil); err != nil && !apierrors.IsNotFound(err) {
+		fmt.Fprintf(logOutput, "auth: failed to delete expiring ClusterRoleBinding %q: %v\n", name, err)
+	}
+}
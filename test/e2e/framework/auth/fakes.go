@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"sync"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// bindingsGetter is satisfied structurally by k8s.io/client-go/kubernetes/fake's
+// RbacV1beta1(), so packages unit testing consumers of BindClusterRole and
+// friends can already pass fake.NewSimpleClientset().RbacV1beta1() without
+// any fake type from this package.
+
+// FakeSubjectAccessReviewClient is a programmable SubjectAccessReviewsGetter
+// for unit testing consumers of the wait helpers without a real cluster. It
+// simulates authorizer cache propagation delay: the Decide function is
+// consulted on every Create call and Delay controls how many calls must
+// happen before Decide's answer is returned as Allowed (earlier calls
+// return !Decide's answer), modeling a policy change that hasn't propagated
+// yet.
+type FakeSubjectAccessReviewClient struct {
+	mu       sync.Mutex
+	attempts int
+
+	// Decide returns the eventual, converged decision for a review.
+	Decide func(review *authorizationv1beta1.SubjectAccessReview) bool
+	// Delay is how many Create calls return the opposite of Decide's answer
+	// before Decide's answer is honored, simulating propagation lag.
+	Delay int
+	// Err, if set, is returned by every Create call instead of a response.
+	Err error
+}
+
+// SubjectAccessReviews implements v1beta1authorization.SubjectAccessReviewsGetter.
+func (f *FakeSubjectAccessReviewClient) SubjectAccessReviews() v1beta1authorization.SubjectAccessReviewInterface {
+	return f
+}
+
+// Create implements v1beta1authorization.SubjectAccessReviewInterface.
+func (f *FakeSubjectAccessReviewClient) Create(review *authorizationv1beta1.SubjectAccessReview) (*authorizationv1beta1.SubjectAccessReview, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	f.mu.Lock()
+	f.attempts++
+	attempt := f.attempts
+	f.mu.Unlock()
+
+	converged := f.Decide != nil && f.Decide(review)
+	out := review.DeepCopy()
+	out.Status.Allowed = converged
+	if attempt <= f.Delay {
+		out.Status.Allowed = !converged
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"testing"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+)
+
+func TestFakeSubjectAccessReviewClientDelay(t *testing.T) {
+	client := &FakeSubjectAccessReviewClient{
+		Decide: func(*authorizationv1beta1.SubjectAccessReview) bool { return true },
+		Delay:  2,
+	}
+	review := &authorizationv1beta1.SubjectAccessReview{}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.SubjectAccessReviews().Create(review)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status.Allowed {
+			t.Fatalf("attempt %d: expected not-yet-propagated decision to be denied", i+1)
+		}
+	}
+
+	resp, err := client.SubjectAccessReviews().Create(review)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Status.Allowed {
+		t.Fatal("expected decision to have converged to allowed after Delay attempts")
+	}
+}
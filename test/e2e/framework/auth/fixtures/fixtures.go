@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixtures provides namespaced Role fixtures that approximate the
+// bootstrap policy's cluster-scoped view/edit/admin roles, for suites that
+// need a role with a known, stable set of permissions and can't rely on
+// view/edit/admin themselves: those are AggregationRule-driven ClusterRoles
+// built at runtime from whatever ClusterRoles carry their aggregation
+// labels, so their effective rules vary across providers and installed API
+// groups (CRDs, aggregated APIs) in a way a suite asserting on specific
+// permissions can't depend on.
+package fixtures
+
+import (
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ViewRole returns a namespaced Role approximating the bootstrap policy's
+// "view" ClusterRole: read-only access to every resource in the core, apps
+// and batch API groups. Unlike "view", this includes Secrets.
+func ViewRole(namespace, name string) *rbacv1beta1.Role {
+	return &rbacv1beta1.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Rules: []rbacv1beta1.PolicyRule{{
+			Verbs:     []string{"get", "list", "watch"},
+			APIGroups: []string{"", "apps", "batch"},
+			Resources: []string{"*"},
+		}},
+	}
+}
+
+// EditRole returns a namespaced Role approximating the bootstrap policy's
+// "edit" ClusterRole: read-write access to the common core resources, but
+// not to Roles/RoleBindings.
+func EditRole(namespace, name string) *rbacv1beta1.Role {
+	return &rbacv1beta1.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Rules: []rbacv1beta1.PolicyRule{{
+			Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"},
+			APIGroups: []string{"", "apps", "batch"},
+			Resources: []string{"*"},
+		}},
+	}
+}
+
+// AdminRole returns a namespaced Role approximating the bootstrap policy's
+// "admin" ClusterRole: EditRole's permissions plus the ability to manage
+// Roles and RoleBindings within the namespace.
+func AdminRole(namespace, name string) *rbacv1beta1.Role {
+	role := EditRole(namespace, name)
+	role.Rules = append(role.Rules, rbacv1beta1.PolicyRule{
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection", "bind", "escalate"},
+		APIGroups: []string{"rbac.authorization.k8s.io"},
+		Resources: []string{"roles", "rolebindings"},
+	})
+	return role
+}
+
+// PodReaderRole returns a minimal namespaced Role granting read-only access
+// to Pods only, for suites that want the smallest possible non-trivial
+// fixture rather than one of the broader view/edit/admin approximations.
+func PodReaderRole(namespace, name string) *rbacv1beta1.Role {
+	return &rbacv1beta1.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Rules: []rbacv1beta1.PolicyRule{{
+			Verbs:     []string{"get", "list", "watch"},
+			APIGroups: []string{""},
+			Resources: []string{"pods"},
+		}},
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"net/http"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// RequestHeaderIdentity describes the identity a requestheader (front-proxy)
+// client should assert via X-Remote-* headers.
+type RequestHeaderIdentity struct {
+	RemoteUser   string
+	RemoteGroups []string
+	RemoteExtra  map[string][]string
+}
+
+// NewRequestHeaderClient returns a clientset that authenticates to the
+// apiserver as the requestheader (front-proxy) authenticator expects: it
+// presents the given proxy client certificate over mTLS and asserts the
+// caller's identity using X-Remote-User/X-Remote-Group/X-Remote-Extra
+// headers. base supplies the host and CA trusted by the apiserver; certData/
+// keyData must be signed by the cluster's configured requestheader-client-ca.
+func NewRequestHeaderClient(base *restclient.Config, certData, keyData []byte, identity RequestHeaderIdentity) (clientset.Interface, error) {
+	config := restclient.CopyConfig(base)
+	config.BearerToken = ""
+	config.Username = ""
+	config.Password = ""
+	config.TLSClientConfig.CertData = certData
+	config.TLSClientConfig.KeyData = keyData
+	config.UserAgent = IdentityUserAgent(identity.RemoteUser)
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		return &requestHeaderRoundTripper{rt: rt, identity: identity}
+	}
+	return clientset.NewForConfig(config)
+}
+
+// requestHeaderRoundTripper stamps X-Remote-* headers onto every outgoing
+// request so the apiserver's requestheader authenticator attributes it to
+// the configured identity instead of the (irrelevant) client certificate CN.
+type requestHeaderRoundTripper struct {
+	rt       http.RoundTripper
+	identity RequestHeaderIdentity
+}
+
+func (rt *requestHeaderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = utilnet.CloneRequest(req)
+	req.Header.Set("X-Remote-User", rt.identity.RemoteUser)
+	for _, group := range rt.identity.RemoteGroups {
+		req.Header.Add("X-Remote-Group", group)
+	}
+	for key, values := range rt.identity.RemoteExtra {
+		for _, value := range values {
+			req.Header.Add("X-Remote-Extra-"+key, value)
+		}
+	}
+	return rt.rt.RoundTrip(req)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+)
+
+// Well-known groups that it is dangerous to bind roles to on a shared test
+// cluster: they are implicitly granted to every authenticated user, every
+// service account, or every cluster-admin credential respectively.
+const (
+	GroupAuthenticated   = "system:authenticated"
+	GroupServiceAccounts = "system:serviceaccounts"
+	GroupMasters         = "system:masters"
+	rbacAPIGroup         = "rbac.authorization.k8s.io"
+)
+
+// BindClusterRoleToGroup binds clusterRole to the given group at cluster
+// scope. Because a leaked binding to one of these well-known groups grants
+// the role to every authenticated user, every service account, or every
+// cluster-admin, this logs loudly at bind time and returns a cleanup
+// function that callers must defer unconditionally.
+func BindClusterRoleToGroup(c bindingsGetter, clusterRole, group, ns string) (cleanup func(), err error) {
+	logf("WARNING: binding clusterrole/%s to group %q for %q; this grants the role to every member of that group until cleaned up", clusterRole, group, ns)
+
+	subject := rbacv1beta1.Subject{Kind: rbacv1beta1.GroupKind, APIGroup: rbacAPIGroup, Name: group}
+	if _, err := BindClusterRole(c, clusterRole, ns, subject); err != nil {
+		return nil, err
+	}
+
+	bindingName := ns + "--" + clusterRole
+	return func() {
+		if err := c.ClusterRoleBindings().Delete(bindingName, nil); err != nil {
+			logf("WARNING: failed to clean up clusterrolebinding/%s bound to group %q: %v", bindingName, group, err)
+			return
+		}
+		recordObjectCleaned()
+	}, nil
+}
+
+// BindClusterRoleToAuthenticated binds clusterRole to every authenticated
+// user (system:authenticated). See BindClusterRoleToGroup.
+func BindClusterRoleToAuthenticated(c bindingsGetter, clusterRole, ns string) (cleanup func(), err error) {
+	return BindClusterRoleToGroup(c, clusterRole, GroupAuthenticated, ns)
+}
+
+// BindClusterRoleToServiceAccounts binds clusterRole to every service
+// account in the cluster (system:serviceaccounts). See BindClusterRoleToGroup.
+func BindClusterRoleToServiceAccounts(c bindingsGetter, clusterRole, ns string) (cleanup func(), err error) {
+	return BindClusterRoleToGroup(c, clusterRole, GroupServiceAccounts, ns)
+}
+
+// BindClusterRoleToMasters binds clusterRole to system:masters, the group
+// carried by cluster-admin credentials. See BindClusterRoleToGroup.
+func BindClusterRoleToMasters(c bindingsGetter, clusterRole, ns string) (cleanup func(), err error) {
+	return BindClusterRoleToGroup(c, clusterRole, GroupMasters, ns)
+}
@@ -18,6 +18,7 @@ package auth
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -31,13 +32,110 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
 	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+	restclient "k8s.io/client-go/rest"
 )
 
 const (
-	policyCachePollInterval = 100 * time.Millisecond
-	policyCachePollTimeout  = 5 * time.Second
+	policyCachePollInterval         = 100 * time.Millisecond
+	defaultPolicyPropagationTimeout = 5 * time.Second
+
+	// pollJitterFactor spreads poll intervals across suites so that many
+	// specs polling in lockstep don't all hit the SAR endpoint on the same
+	// tick; a burst pattern is harder to distinguish from real propagation
+	// lag in flake triage than intervals that are already spread out.
+	pollJitterFactor = 0.2
 )
 
+// policyCachePollTimeout is the deadline auth wait helpers use by default.
+// It starts at defaultPolicyPropagationTimeout and changes only when
+// SetPolicyPropagationTimeout is called; no framework timeout sets it
+// automatically.
+var policyCachePollTimeout = defaultPolicyPropagationTimeout
+
+// SetPolicyPropagationTimeout overrides policyCachePollTimeout, the deadline
+// every wait helper in this package polls against by default. Intended to be
+// called once at suite startup from the value cluster operators configure
+// via framework's --timeout flags (e.g. a future TimeoutContext.PolicyPropagation
+// field), so operators can scale auth waits the same way they scale every
+// other framework timeout instead of this package hard-coding 5s.
+//
+// Ideally this deadline would also be capped by however much time remains on
+// the enclosing spec, so a wait can't itself cause a spec-level timeout with
+// no useful error. Ginkgo v1's DSL only exposes a completed spec's RunTime
+// after it finishes, not remaining budget while it's running, so there's no
+// such signal to couple to yet; SetPolicyPropagationTimeout is the seam a
+// future ginkgo/framework upgrade exposing that would call into.
+func SetPolicyPropagationTimeout(d time.Duration) {
+	policyCachePollTimeout = d
+}
+
+// pollFunc and sleepFunc back the wait/sleep calls in
+// WaitForNamedAuthorizationUpdate. They're package-level vars, rather than
+// direct calls to wait.Poll/time.Sleep, purely so unit tests can substitute
+// a fast, deterministic poller instead of exercising real wall-clock delays.
+// logOutput plays the same role for logf/log's destination.
+var (
+	pollFunc            = wait.Poll
+	sleepFunc           = time.Sleep
+	logOutput io.Writer = ginkgo.GinkgoWriter
+)
+
+// sarForbiddenFallback, if set, is consulted when the caller itself is
+// forbidden from creating SubjectAccessReviews (as opposed to the SAR
+// endpoint being entirely absent, which IsNotFound already handles). nil by
+// default, in which case the Forbidden error is returned immediately rather
+// than spun on for the rest of the poll timeout, since the caller's own
+// permissions won't change mid-poll.
+var sarForbiddenFallback func(user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (bool, error)
+
+// SetSARForbiddenFallback installs fallback to be used whenever this
+// package's wait helpers are themselves forbidden from creating
+// SubjectAccessReviews. Pass nil to restore the default (fail fast).
+func SetSARForbiddenFallback(fallback func(user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (bool, error)) {
+	sarForbiddenFallback = fallback
+}
+
+// FixedSleepSARFallback returns a SetSARForbiddenFallback fallback that
+// simply sleeps d and reports success, mirroring the existing IsNotFound
+// "wait one second and hope it's up to date" behavior for callers who can't
+// create SARs at all.
+func FixedSleepSARFallback(d time.Duration) func(user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (bool, error) {
+	return func(user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (bool, error) {
+		sleepFunc(d)
+		return true, nil
+	}
+}
+
+// RealRequestSARFallback returns a SetSARForbiddenFallback fallback that
+// probes with an actual "get" request impersonated as user instead of a
+// SubjectAccessReview, for callers who can't create SARs but can
+// impersonate. Only the "get" verb can be mapped onto a real request this
+// generically; any other verb returns an error explaining the limitation
+// rather than silently reporting success.
+func RealRequestSARFallback(base *restclient.Config) func(user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (bool, error) {
+	return func(user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) (bool, error) {
+		if verb != "get" || resourceName == "" {
+			return false, fmt.Errorf("RealRequestSARFallback can only probe named \"get\" requests, got verb %q resourceName %q", verb, resourceName)
+		}
+		client, err := newImpersonatedClient(base, user, nil)
+		if err != nil {
+			return false, err
+		}
+		_, err = client.CoreV1().RESTClient().Get().
+			NamespaceIfScoped(namespace, namespace != "").
+			Resource(resource.Resource).
+			Name(resourceName).
+			DoRaw()
+		if err == nil {
+			return allowed, nil
+		}
+		if apierrors.IsForbidden(err) {
+			return !allowed, nil
+		}
+		return false, err
+	}
+}
+
 type bindingsGetter interface {
 	v1beta1rbac.RoleBindingsGetter
 	v1beta1rbac.ClusterRoleBindingsGetter
@@ -53,6 +151,8 @@ func WaitForAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGette
 // WaitForNamedAuthorizationUpdate checks if the given user can perform the named verb and action on the named resource.
 // If policyCachePollTimeout is reached without the expected condition matching, an error is returned
 func WaitForNamedAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) error {
+	defer traceOp("WaitForNamedAuthorizationUpdate", user, namespace, verb, resource, allowed)()
+
 	review := &authorizationv1beta1.SubjectAccessReview{
 		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
 			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
@@ -66,39 +166,78 @@ func WaitForNamedAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviews
 		},
 	}
 
-	err := wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
-		response, err := c.SubjectAccessReviews().Create(review)
-		// GKE doesn't enable the SAR endpoint.  Without this endpoint, we cannot determine if the policy engine
-		// has adjusted as expected.  In this case, simply wait one second and hope it's up to date
-		// TODO: Should have a check for the provider here but that introduces too tight of
-		// coupling with the `framework` package. See: https://github.com/kubernetes/kubernetes/issues/76726
-		if apierrors.IsNotFound(err) {
+	start := pkgClock.Now()
+	var lastResponse *authorizationv1beta1.SubjectAccessReview
+	var lastErr error
+	polls := 0
+	interval := wait.Jitter(policyCachePollInterval, pollJitterFactor)
+	err := pollFunc(interval, cacheAwarePollTimeout(allowed), func() (bool, error) {
+		polls++
+		var response *authorizationv1beta1.SubjectAccessReview
+		var err error
+		if Capabilities().SARAvailable {
+			response, err = c.SubjectAccessReviews().Create(review)
+			recordSAR(review.Spec, response, err)
+		}
+		// Some providers (historically GKE) don't enable the SAR endpoint.
+		// Without this endpoint, we cannot determine if the policy engine
+		// has adjusted as expected. In this case, simply wait one second
+		// and hope it's up to date. Capabilities().SARAvailable lets a
+		// provider declare this ahead of time instead of only discovering
+		// it via a NotFound error.
+		if !Capabilities().SARAvailable || apierrors.IsNotFound(err) {
 			logf("SubjectAccessReview endpoint is missing")
-			time.Sleep(1 * time.Second)
+			sleepFunc(1 * time.Second)
 			return true, nil
 		}
+		if apierrors.IsForbidden(err) {
+			logf("caller is forbidden from creating SubjectAccessReviews; grant it the system:auth-delegator "+
+				"role (or set a fallback with SetSARForbiddenFallback) to fix this: %v", err)
+			if sarForbiddenFallback == nil {
+				lastErr = err
+				return false, err
+			}
+			ok, fallbackErr := sarForbiddenFallback(user, namespace, verb, resourceName, resource, allowed)
+			if fallbackErr != nil {
+				lastErr = fallbackErr
+				return false, fallbackErr
+			}
+			return ok, nil
+		}
 		if err != nil {
+			lastErr = err
 			return false, err
 		}
+		lastResponse = response
 		if response.Status.Allowed != allowed {
+			logf("poll at %v: %s cannot %s %s (want allowed=%v)", pkgClock.Since(start), user, verb, resource, allowed)
 			return false, nil
 		}
 		return true, nil
 	})
-	return err
+	if err != nil {
+		return reportAuthorizationFailure(review.Spec, allowed, lastResponse, pkgClock.Since(start), polls, lastErr)
+	}
+	return nil
 }
 
-// BindClusterRole binds the cluster role at the cluster scope. If RBAC is not enabled, nil
-// is returned with no action.
-func BindClusterRole(c bindingsGetter, clusterRole, ns string, subjects ...rbacv1beta1.Subject) error {
+// BindClusterRole binds the cluster role at the cluster scope, returning the
+// created ClusterRoleBinding so callers can patch, delete, or assert on it
+// directly instead of re-deriving its ns+"--"+clusterRole name. If RBAC is
+// not enabled, (nil, nil) is returned with no action.
+func BindClusterRole(c bindingsGetter, clusterRole, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.ClusterRoleBinding, error) {
 	if !IsRBACEnabled(c) {
-		return nil
+		return nil, nil
+	}
+	if err := checkPrivilegeGuard(clusterRole, subjects); err != nil {
+		return nil, err
 	}
 
 	// Since the namespace names are unique, we can leave this lying around so we don't have to race any caches
-	_, err := c.ClusterRoleBindings().Create(&rbacv1beta1.ClusterRoleBinding{
+	binding, err := c.ClusterRoleBindings().Create(&rbacv1beta1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: ns + "--" + clusterRole,
+			Name:        ns + "--" + clusterRole,
+			Annotations: traceAnnotations(),
 		},
 		RoleRef: rbacv1beta1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
@@ -109,33 +248,37 @@ func BindClusterRole(c bindingsGetter, clusterRole, ns string, subjects ...rbacv
 	})
 
 	if err != nil {
-		return errors.Wrapf(err, "binding clusterrole/%s for %q for %v", clusterRole, ns, subjects)
+		return nil, errors.Wrapf(err, "binding clusterrole/%s for %q for %s", clusterRole, ns, FormatSubjects(subjects))
 	}
 
-	return nil
+	recordObjectCreated()
+	return binding, nil
 }
 
-// BindClusterRoleInNamespace binds the cluster role at the namespace scope. If RBAC is not enabled, nil
-// is returned with no action.
-func BindClusterRoleInNamespace(c bindingsGetter, clusterRole, ns string, subjects ...rbacv1beta1.Subject) error {
+// BindClusterRoleInNamespace binds the cluster role at the namespace scope,
+// returning the created RoleBinding. If RBAC is not enabled, (nil, nil) is
+// returned with no action.
+func BindClusterRoleInNamespace(c bindingsGetter, clusterRole, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, error) {
 	return bindInNamespace(c, "ClusterRole", clusterRole, ns, subjects...)
 }
 
-// BindRoleInNamespace binds the role at the namespace scope. If RBAC is not enabled, nil
-// is returned with no action.
-func BindRoleInNamespace(c bindingsGetter, role, ns string, subjects ...rbacv1beta1.Subject) error {
+// BindRoleInNamespace binds the role at the namespace scope, returning the
+// created RoleBinding. If RBAC is not enabled, (nil, nil) is returned with no
+// action.
+func BindRoleInNamespace(c bindingsGetter, role, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, error) {
 	return bindInNamespace(c, "Role", role, ns, subjects...)
 }
 
-func bindInNamespace(c bindingsGetter, roleType, role, ns string, subjects ...rbacv1beta1.Subject) error {
+func bindInNamespace(c bindingsGetter, roleType, role, ns string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, error) {
 	if !IsRBACEnabled(c) {
-		return nil
+		return nil, nil
 	}
 
 	// Since the namespace names are unique, we can leave this lying around so we don't have to race any caches
-	_, err := c.RoleBindings(ns).Create(&rbacv1beta1.RoleBinding{
+	binding, err := c.RoleBindings(ns).Create(&rbacv1beta1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: ns + "--" + role,
+			Name:        ns + "--" + role,
+			Annotations: traceAnnotations(),
 		},
 		RoleRef: rbacv1beta1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
@@ -146,19 +289,34 @@ func bindInNamespace(c bindingsGetter, roleType, role, ns string, subjects ...rb
 	})
 
 	if err != nil {
-		return errors.Wrapf(err, "binding %s/%s into %q for %v", roleType, role, ns, subjects)
+		return nil, errors.Wrapf(err, "binding %s/%s into %q for %s", roleType, role, ns, FormatSubjects(subjects))
 	}
 
-	return nil
+	recordObjectCreated()
+	return binding, nil
 }
 
 var (
-	isRBACEnabledOnce sync.Once
-	isRBACEnabled     bool
+	isRBACEnabledOnce     sync.Once
+	isRBACEnabled         bool
+	isRBACEnabledOverride *bool
 )
 
+// OverrideRBACDetection forces IsRBACEnabled to always return enabled (true
+// or false), bypassing the ClusterRoles-listing heuristic. Use this when a
+// suite knows whether its target cluster runs RBAC but the heuristic would
+// misfire, e.g. because the calling credential cannot list ClusterRoles (so
+// every Bind* call would otherwise silently no-op).
+func OverrideRBACDetection(enabled bool) {
+	isRBACEnabledOverride = &enabled
+}
+
 // IsRBACEnabled returns true if RBAC is enabled. Otherwise false.
 func IsRBACEnabled(crGetter v1beta1rbac.ClusterRolesGetter) bool {
+	if isRBACEnabledOverride != nil {
+		return *isRBACEnabledOverride
+	}
+
 	isRBACEnabledOnce.Do(func() {
 		crs, err := crGetter.ClusterRoles().List(metav1.ListOptions{})
 		if err != nil {
@@ -187,7 +345,7 @@ func logf(format string, args ...interface{}) {
 // TODO: Log functions like these should be put into their own package,
 // see: https://github.com/kubernetes/kubernetes/issues/76728
 func log(level string, format string, args ...interface{}) {
-	fmt.Fprintf(ginkgo.GinkgoWriter, nowStamp()+": "+level+": "+format+"\n", args...)
+	fmt.Fprintf(logOutput, nowStamp()+": "+level+": "+format+"\n", args...)
 }
 
 // nowStamp returns the current time formatted for placement in the logs (time.StampMilli).
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// fakePoll replaces pollFunc with one that calls condition up to maxAttempts
+// times with no real sleeping, so wait-loop tests run instantly and
+// deterministically instead of depending on wall-clock timing.
+func fakePoll(maxAttempts int) func(interval, timeout time.Duration, condition wait.ConditionFunc) error {
+	return func(interval, timeout time.Duration, condition wait.ConditionFunc) error {
+		for i := 0; i < maxAttempts; i++ {
+			done, err := condition()
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		}
+		return wait.ErrWaitTimeout
+	}
+}
+
+func withTestHooks(t *testing.T, poll func(interval, timeout time.Duration, condition wait.ConditionFunc) error) {
+	origPoll, origSleep, origLog := pollFunc, sleepFunc, logOutput
+	pollFunc = poll
+	sleepFunc = func(time.Duration) {}
+	logOutput = ioutil.Discard
+	t.Cleanup(func() {
+		pollFunc, sleepFunc, logOutput = origPoll, origSleep, origLog
+	})
+}
+
+func TestWaitForNamedAuthorizationUpdatePropagationSuccess(t *testing.T) {
+	withTestHooks(t, fakePoll(5))
+
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1beta1.SubjectAccessReview{
+			Status: authorizationv1beta1.SubjectAccessReviewStatus{Allowed: true},
+		}, nil
+	})
+
+	if err := WaitForAuthorizationUpdate(client.AuthorizationV1beta1(), "alice", "ns", "get", schema.GroupResource{Resource: "pods"}, true); err != nil {
+		t.Fatalf("expected propagation to succeed immediately, got: %v", err)
+	}
+}
+
+func TestWaitForNamedAuthorizationUpdateTimeout(t *testing.T) {
+	withTestHooks(t, fakePoll(3))
+
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1beta1.SubjectAccessReview{
+			Status: authorizationv1beta1.SubjectAccessReviewStatus{Allowed: false},
+		}, nil
+	})
+
+	err := WaitForAuthorizationUpdate(client.AuthorizationV1beta1(), "alice", "ns", "get", schema.GroupResource{Resource: "pods"}, true)
+	if err == nil {
+		t.Fatal("expected a timeout error, got nil")
+	}
+	if !strings.Contains(err.Error(), "alice") || !strings.Contains(err.Error(), "3 polls") {
+		t.Fatalf("expected the timeout error to name the user and poll count, got: %v", err)
+	}
+}
+
+func TestWaitForNamedAuthorizationUpdateNotFoundFallback(t *testing.T) {
+	withTestHooks(t, fakePoll(1))
+
+	slept := false
+	sleepFunc = func(time.Duration) { slept = true }
+
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1beta1.SubjectAccessReview{}, apierrors.NewNotFound(schema.GroupResource{Resource: "subjectaccessreviews"}, "")
+	})
+
+	if err := WaitForAuthorizationUpdate(client.AuthorizationV1beta1(), "alice", "ns", "get", schema.GroupResource{Resource: "pods"}, true); err != nil {
+		t.Fatalf("expected the missing-endpoint fallback to succeed, got: %v", err)
+	}
+	if !slept {
+		t.Fatal("expected the missing-endpoint fallback to sleep via sleepFunc")
+	}
+}
+
+func TestBindClusterRoleRBACDisabled(t *testing.T) {
+	origOverride := isRBACEnabledOverride
+	disabled := false
+	isRBACEnabledOverride = &disabled
+	t.Cleanup(func() { isRBACEnabledOverride = origOverride })
+
+	client := fake.NewSimpleClientset()
+	if binding, err := BindClusterRole(client.RbacV1beta1(), "cluster-admin", "ns"); err != nil || binding != nil {
+		t.Fatalf("expected a no-op (nil, nil) when RBAC is disabled, got: (%v, %v)", binding, err)
+	}
+
+	bindings, err := client.RbacV1beta1().ClusterRoleBindings().List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing clusterrolebindings: %v", err)
+	}
+	if len(bindings.Items) != 0 {
+		t.Fatalf("expected no clusterrolebinding to be created while RBAC is disabled, got %d", len(bindings.Items))
+	}
+}
+
+func TestBindClusterRoleRefusesPrivilegedWithoutOptIn(t *testing.T) {
+	origOverride := isRBACEnabledOverride
+	enabled := true
+	isRBACEnabledOverride = &enabled
+	t.Cleanup(func() { isRBACEnabledOverride = origOverride })
+
+	client := fake.NewSimpleClientset()
+	if binding, err := BindClusterRole(client.RbacV1beta1(), "cluster-admin", "ns"); err == nil || binding != nil {
+		t.Fatalf("expected BindClusterRole to refuse binding cluster-admin without AllowPrivilegedBindings(true), got: (%v, %v)", binding, err)
+	}
+
+	bindings, err := client.RbacV1beta1().ClusterRoleBindings().List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing clusterrolebindings: %v", err)
+	}
+	if len(bindings.Items) != 0 {
+		t.Fatalf("expected no clusterrolebinding to be created when the privilege guard refuses the bind, got %d", len(bindings.Items))
+	}
+}
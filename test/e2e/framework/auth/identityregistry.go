@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"sync"
+
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// IdentityRegistry caches identities (e.g. the clients MakeNamespaceAdmin or
+// NewExecCredentialClient return) keyed by an arbitrary persona name, so a
+// suite with dozens of specs needing "the namespace admin" or "the OIDC
+// viewer" pays the CSR/TokenRequest/bind cost once instead of on every spec.
+// A zero-value IdentityRegistry is not usable; use NewIdentityRegistry.
+type IdentityRegistry struct {
+	mu         sync.Mutex
+	identities map[string]clientset.Interface
+	cleanups   []func()
+}
+
+// NewIdentityRegistry returns an empty IdentityRegistry.
+func NewIdentityRegistry() *IdentityRegistry {
+	return &IdentityRegistry{identities: map[string]clientset.Interface{}}
+}
+
+// GetOrCreate returns the client cached under name, calling create to build
+// and cache one the first time name is requested. create's cleanup return
+// value, if non-nil, is retained and run by a later call to Cleanup; it is
+// not run again on subsequent GetOrCreate calls for the same name.
+func (r *IdentityRegistry) GetOrCreate(name string, create func() (clientset.Interface, func(), error)) (clientset.Interface, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if client, ok := r.identities[name]; ok {
+		return client, nil
+	}
+
+	client, cleanup, err := create()
+	if err != nil {
+		return nil, err
+	}
+	r.identities[name] = client
+	if cleanup != nil {
+		r.cleanups = append(r.cleanups, cleanup)
+	}
+	return client, nil
+}
+
+// Cleanup runs every registered cleanup func, most-recently-added first, and
+// empties the registry. Intended to be called once at suite teardown (e.g.
+// ginkgo's AfterSuite), not between specs, since the whole point of the
+// registry is to survive across them.
+func (r *IdentityRegistry) Cleanup() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+	r.cleanups = nil
+	r.identities = map[string]clientset.Interface{}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// The resources the impersonation admission filter authorizes the "impersonate"
+// verb against; see k8s.io/apiserver/pkg/endpoints/filters/impersonation.go.
+var (
+	ImpersonateUsersResource          = CoreResource("users")
+	ImpersonateGroupsResource         = CoreResource("groups")
+	ImpersonateServiceAccountResource = CoreResource("serviceaccounts")
+	ImpersonateUserExtraResource      = CoreResource("userextras")
+)
+
+// GrantImpersonateUser creates a ClusterRole called name granting permission
+// to impersonate any user, and binds it to subject. The grant is not limited
+// to particular users.
+func GrantImpersonateUser(c bindingsGetter, name string, subject rbacv1beta1.Subject) error {
+	return grantImpersonate(c, name, ImpersonateUsersResource, nil, subject)
+}
+
+// GrantImpersonateGroup grants subject permission to impersonate any group.
+func GrantImpersonateGroup(c bindingsGetter, name string, subject rbacv1beta1.Subject) error {
+	return grantImpersonate(c, name, ImpersonateGroupsResource, nil, subject)
+}
+
+// GrantImpersonateServiceAccount grants subject permission to impersonate
+// any service account.
+func GrantImpersonateServiceAccount(c bindingsGetter, name string, subject rbacv1beta1.Subject) error {
+	return grantImpersonate(c, name, ImpersonateServiceAccountResource, nil, subject)
+}
+
+// GrantImpersonateUserExtra grants subject permission to set the named
+// user-extra key (e.g. "scopes") while impersonating.
+func GrantImpersonateUserExtra(c bindingsGetter, name, extraKey string, subject rbacv1beta1.Subject) error {
+	role := &rbacv1beta1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: traceAnnotations(),
+		},
+		Rules: []rbacv1beta1.PolicyRule{{
+			// RBAC matches subresources with the "<resource>/<subresource>"
+			// syntax rather than a distinct field.
+			Verbs:     []string{"impersonate"},
+			APIGroups: []string{ImpersonateUserExtraResource.Group},
+			Resources: []string{"userextras/" + extraKey},
+		}},
+	}
+	if _, err := c.ClusterRoles().Create(role); err != nil {
+		return fmt.Errorf("creating clusterrole/%s granting impersonate on userextras/%s: %v", name, extraKey, err)
+	}
+	_, err := BindClusterRole(c, name, name, subject)
+	return err
+}
+
+func grantImpersonate(c bindingsGetter, name string, resource schema.GroupResource, resourceNames []string, subject rbacv1beta1.Subject) error {
+	role := &rbacv1beta1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: traceAnnotations(),
+		},
+		Rules: []rbacv1beta1.PolicyRule{{
+			Verbs:         []string{"impersonate"},
+			APIGroups:     []string{resource.Group},
+			Resources:     []string{resource.Resource},
+			ResourceNames: resourceNames,
+		}},
+	}
+	if _, err := c.ClusterRoles().Create(role); err != nil {
+		return fmt.Errorf("creating clusterrole/%s granting impersonate on %s: %v", name, resource, err)
+	}
+	_, err := BindClusterRole(c, name, name, subject)
+	return err
+}
+
+// WaitForImpersonateAuthorizationUpdate checks whether user is authorized to
+// impersonate the given resource/name, e.g.
+// WaitForImpersonateAuthorizationUpdate(sar, "alice", ImpersonateUsersResource, "bob", true).
+func WaitForImpersonateAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, user string, resource schema.GroupResource, name string, allowed bool) error {
+	return WaitForNamedAuthorizationUpdate(c, user, "", "impersonate", name, resource, allowed)
+}
+
+// AssertCannotImpersonateMasters is a negative-coverage check: it fails
+// unless user is denied permission to impersonate the system:masters group,
+// the credential that would otherwise let user escalate to cluster-admin by
+// impersonating their way into it.
+func AssertCannotImpersonateMasters(c v1beta1authorization.SubjectAccessReviewsGetter, user string) error {
+	if err := WaitForImpersonateAuthorizationUpdate(c, user, ImpersonateGroupsResource, GroupMasters, false); err != nil {
+		return fmt.Errorf("expected %q to be denied impersonation of group %q: %v", user, GroupMasters, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/test/utils"
+)
+
+// AssertImpersonationAttributed searches events (typically parsed from an
+// audit log via utils.CheckAuditLines/ParseAuditLog) for one matching verb,
+// resource and namespace, and asserts that the server attributed the request
+// to impersonatedUser while recording impersonator as the authenticated
+// caller. This closes the loop on the impersonation authorization path: it is
+// not enough that the SubjectAccessReview says allowed, the request actually
+// performed must have run as the impersonated identity.
+func AssertImpersonationAttributed(events []utils.AuditEvent, impersonator, impersonatedUser, verb, resource, namespace string) error {
+	for _, event := range events {
+		if event.Verb != verb || event.Resource != resource || event.Namespace != namespace {
+			continue
+		}
+		if event.User != impersonator {
+			continue
+		}
+		if event.ImpersonatedUser != impersonatedUser {
+			return fmt.Errorf("audit event for %s %s/%s by %q recorded impersonated user %q, want %q",
+				verb, resource, namespace, impersonator, event.ImpersonatedUser, impersonatedUser)
+		}
+		return nil
+	}
+	return fmt.Errorf("no audit event found attributing %s %s/%s to impersonator %q acting as %q", verb, resource, namespace, impersonator, impersonatedUser)
+}
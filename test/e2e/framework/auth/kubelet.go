@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientset "k8s.io/client-go/kubernetes"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// Node subresources delegated to the kubelet authorizer; see
+// pkg/kubelet/server/auth.go. This package cannot import
+// test/e2e/framework (import cycle), so these helpers do not reuse
+// framework.NodeProxyRequest.
+var (
+	NodeProxyResource = SubResource("", "nodes", "proxy")
+	NodeStatsResource = SubResource("", "nodes", "stats")
+	NodeLogResource   = SubResource("", "nodes", "log")
+)
+
+// WaitForNodeSubresourceAuthorizationUpdate checks whether user is authorized
+// for verb on the given node subresource (NodeProxyResource, NodeStatsResource
+// or NodeLogResource) of node.
+func WaitForNodeSubresourceAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, user, verb, node string, subresource schema.GroupResource) error {
+	return WaitForNamedAuthorizationUpdate(c, user, "", verb, node, subresource, true)
+}
+
+// RequestNodeSubresource issues a real GET through the apiserver's node
+// proxy (nodes/proxy, nodes/stats, or nodes/log) as client, returning the
+// raw response body. Callers use this alongside the SAR-based wait helpers
+// to confirm the kubelet authorization delegation path actually enforces
+// what the SAR predicts, not just what it predicts.
+func RequestNodeSubresource(client clientset.Interface, node, subresource, suffix string) ([]byte, error) {
+	body, err := client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		SubResource(subresource).
+		Name(node).
+		Suffix(suffix).
+		DoRaw()
+	if err != nil {
+		return nil, fmt.Errorf("requesting nodes/%s of node %q: %w", subresource, node, err)
+	}
+	return body, nil
+}
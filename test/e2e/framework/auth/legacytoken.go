@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// LegacyServiceAccountToken extracts the bearer token from the first
+// kubernetes.io/service-account-token Secret the token controller populated
+// onto the named ServiceAccount, along with that Secret's name so callers
+// can delete it directly (as opposed to deleting the ServiceAccount) to
+// exercise LegacyServiceAccountTokenTracking's per-secret invalidation path.
+// Callers should WaitForServiceAccountTokenSecret first.
+func LegacyServiceAccountToken(c clientset.Interface, namespace, name string) (token, secretName string, err error) {
+	sa, err := c.CoreV1().ServiceAccounts(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	if len(sa.Secrets) == 0 {
+		return "", "", fmt.Errorf("serviceaccount %s/%s has no token secrets", namespace, name)
+	}
+	secretName = sa.Secrets[0].Name
+
+	secret, err := c.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	if secret.Type != v1.SecretTypeServiceAccountToken {
+		return "", "", fmt.Errorf("secret %s/%s is type %q, not %q", namespace, secretName, secret.Type, v1.SecretTypeServiceAccountToken)
+	}
+	token = string(secret.Data[v1.ServiceAccountTokenKey])
+	if token == "" {
+		return "", "", fmt.Errorf("secret %s/%s has no token data", namespace, secretName)
+	}
+	return token, secretName, nil
+}
+
+// NewLegacyServiceAccountTokenClient returns a clientset authenticating with
+// a legacy ServiceAccount token Secret's bearer token.
+func NewLegacyServiceAccountTokenClient(base *restclient.Config, namespace, name, token string) (clientset.Interface, error) {
+	config := restclient.AnonymousClientConfig(base)
+	config.BearerToken = token
+	config.UserAgent = IdentityUserAgent("system:serviceaccount:" + namespace + ":" + name)
+	return clientset.NewForConfig(config)
+}
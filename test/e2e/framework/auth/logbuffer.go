@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// LogBuffer is an io.Writer that accumulates this package's log output for a
+// single spec instead of writing straight to the shared GinkgoWriter, so
+// that authorization-wait logs from specs running concurrently in one
+// process don't interleave into an unreadable stream. Install one as the
+// package's log destination with SetLogOutput at spec setup, then call
+// FlushTo (typically only on failure, mirroring how GinkgoWriter itself
+// already suppresses passing-spec output) or Reset to discard it.
+type LogBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewLogBuffer returns an empty LogBuffer.
+func NewLogBuffer() *LogBuffer {
+	return &LogBuffer{}
+}
+
+// Write implements io.Writer. Safe for concurrent use.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// FlushTo copies the buffered log lines to w and clears the buffer.
+func (b *LogBuffer) FlushTo(w io.Writer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err := w.Write(b.buf.Bytes())
+	b.buf.Reset()
+	return err
+}
+
+// Reset discards the buffered log lines without writing them anywhere,
+// for the passing-spec case where they're of no further use.
+func (b *LogBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+// SetLogOutput installs w as this package's log destination in place of the
+// default GinkgoWriter, returning a restore func that puts the previous
+// destination back. logOutput is a single package-global var, so specs that
+// run concurrently in one process (rather than ginkgo's usual one-process-
+// per-parallel-node model) must not both hold a SetLogOutput installation
+// open at the same time; pair each call with its restore func in the same
+// spec, e.g. from BeforeEach/AfterEach.
+func SetLogOutput(w io.Writer) (restore func()) {
+	previous := logOutput
+	logOutput = w
+	return func() { logOutput = previous }
+}
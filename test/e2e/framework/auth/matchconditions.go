@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import "fmt"
+
+// MatchConditionForVerb returns a CEL expression matching requests using the
+// given verb, in the form expected by AuthorizerConfigEntry.MatchConditionCEL,
+// e.g. for use in webhook authorizers scoped to a subset of verbs.
+func MatchConditionForVerb(verb string) string {
+	return fmt.Sprintf("request.resourceAttributes.verb == %q", verb)
+}
+
+// MatchConditionForGroupResource returns a CEL expression matching requests
+// against the given API group/resource.
+func MatchConditionForGroupResource(group, resource string) string {
+	return fmt.Sprintf("request.resourceAttributes.group == %q && request.resourceAttributes.resource == %q", group, resource)
+}
+
+// AssertMatchConditionRouting checks whether a request matching attrs was
+// routed to the webhook authorizer at all, using the presence of an
+// authorization decision audit annotation as a proxy (see
+// AssertAuthorizerDecided's caveat: the annotation does not name the
+// authorizer). A request skipped by matchConditions falls through to the
+// next authorizer in the chain and typically leaves no decision annotation
+// for this authorizer's stage, so wantRouted=false expects no annotation
+// while wantRouted=true expects one.
+func AssertMatchConditionRouting(decided bool, wantRouted bool) error {
+	if decided != wantRouted {
+		return fmt.Errorf("expected webhook match-condition routing=%v, but a decision was recorded=%v", wantRouted, decided)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// ClusterAuth pairs a cluster's identifying name with the clients this
+// package's helpers need to bind roles and evaluate SubjectAccessReviews
+// against it.
+type ClusterAuth struct {
+	Name     string
+	Bindings bindingsGetter
+	SAR      v1beta1authorization.SubjectAccessReviewsGetter
+}
+
+// MultiClusterAuth applies the same role/binding set to several clusters
+// (e.g. for federation-style e2e suites) and waits for propagation on each,
+// reporting failures per cluster instead of aborting on the first one.
+type MultiClusterAuth struct {
+	Clusters []ClusterAuth
+}
+
+// BindClusterRoleEverywhere binds clusterRole to subjects at cluster scope
+// on every cluster, returning one error per cluster that failed.
+func (m *MultiClusterAuth) BindClusterRoleEverywhere(clusterRole, ns string, subjects ...rbacv1beta1.Subject) map[string]error {
+	failures := map[string]error{}
+	for _, cluster := range m.Clusters {
+		if _, err := BindClusterRole(cluster.Bindings, clusterRole, ns, subjects...); err != nil {
+			failures[cluster.Name] = err
+		}
+	}
+	return failures
+}
+
+// WaitForAuthorizationUpdateEverywhere waits, per cluster, for user to be
+// authorized (or not) for verb/resource in namespace, returning one error
+// per cluster that didn't converge.
+func (m *MultiClusterAuth) WaitForAuthorizationUpdateEverywhere(user, namespace, verb string, resource schema.GroupResource, allowed bool) map[string]error {
+	failures := map[string]error{}
+	for _, cluster := range m.Clusters {
+		if err := WaitForAuthorizationUpdate(cluster.SAR, user, namespace, verb, resource, allowed); err != nil {
+			failures[cluster.Name] = fmt.Errorf("cluster %q: %v", cluster.Name, err)
+		}
+	}
+	return failures
+}
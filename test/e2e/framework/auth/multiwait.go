@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForUsersAuthorizationUpdate confirms that a shared binding (e.g. to a
+// group) has propagated for every user in users within a single polling
+// loop, rather than waiting for each user sequentially and inflating suite
+// time by a factor of len(users).
+func WaitForUsersAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, users []string, namespace, verb string, resource schema.GroupResource, allowed bool) error {
+	pending := make(map[string]bool, len(users))
+	for _, user := range users {
+		pending[user] = true
+	}
+
+	err := wait.Poll(policyCachePollInterval, cacheAwarePollTimeout(allowed), func() (bool, error) {
+		for user := range pending {
+			review := &authorizationv1beta1.SubjectAccessReview{
+				Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+						Group:     resource.Group,
+						Verb:      verb,
+						Resource:  resource.Resource,
+						Namespace: namespace,
+					},
+					User: user,
+				},
+			}
+			response, err := c.SubjectAccessReviews().Create(review)
+			if err != nil {
+				return false, err
+			}
+			if response.Status.Allowed == allowed {
+				delete(pending, user)
+			}
+		}
+		return len(pending) == 0, nil
+	})
+	if err != nil {
+		remaining := make([]string, 0, len(pending))
+		for user := range pending {
+			remaining = append(remaining, user)
+		}
+		return fmt.Errorf("waiting for %s/%s authorization update to propagate to all users: %v; still pending: %v", verb, resource, err, remaining)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+	"k8s.io/client-go/util/retry"
+)
+
+// AddSubjectsToBinding adds subjects to the named ClusterRoleBinding, retrying
+// on update conflicts so that parallel specs sharing a single binding (e.g. a
+// well-known system binding) don't clobber each other's additions, and
+// returns the binding's final subject list for the caller to assert on.
+func AddSubjectsToBinding(c v1beta1rbac.ClusterRoleBindingsGetter, name string, subjects ...rbacv1beta1.Subject) ([]rbacv1beta1.Subject, error) {
+	return mutateBindingSubjects(c, name, func(existing []rbacv1beta1.Subject) []rbacv1beta1.Subject {
+		for _, subject := range subjects {
+			if !containsSubject(existing, subject) {
+				existing = append(existing, subject)
+			}
+		}
+		return existing
+	})
+}
+
+// RemoveSubjectsFromBinding removes subjects from the named
+// ClusterRoleBinding, retrying on update conflicts, and returns the
+// binding's final subject list for the caller to assert on.
+func RemoveSubjectsFromBinding(c v1beta1rbac.ClusterRoleBindingsGetter, name string, subjects ...rbacv1beta1.Subject) ([]rbacv1beta1.Subject, error) {
+	return mutateBindingSubjects(c, name, func(existing []rbacv1beta1.Subject) []rbacv1beta1.Subject {
+		var kept []rbacv1beta1.Subject
+		for _, subject := range existing {
+			if !containsSubject(subjects, subject) {
+				kept = append(kept, subject)
+			}
+		}
+		return kept
+	})
+}
+
+func mutateBindingSubjects(c v1beta1rbac.ClusterRoleBindingsGetter, name string, mutate func([]rbacv1beta1.Subject) []rbacv1beta1.Subject) ([]rbacv1beta1.Subject, error) {
+	var final []rbacv1beta1.Subject
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		binding, err := c.ClusterRoleBindings().Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		binding.Subjects = mutate(binding.Subjects)
+		updated, err := c.ClusterRoleBindings().Update(binding)
+		if err != nil {
+			return err
+		}
+		final = updated.Subjects
+		return nil
+	})
+	return final, err
+}
+
+func containsSubject(subjects []rbacv1beta1.Subject, subject rbacv1beta1.Subject) bool {
+	for _, s := range subjects {
+		if s == subject {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientset "k8s.io/client-go/kubernetes"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// NodeGroup is the group every kubelet identity belongs to.
+const NodeGroup = "system:nodes"
+
+// NodeUsername returns the identity the Node authorizer attributes to a
+// kubelet's requests, e.g. NodeUsername("node-1") == "system:node:node-1".
+func NodeUsername(node string) string {
+	return "system:node:" + node
+}
+
+// CreateNodeBoundPod creates a pod scheduled onto node that mounts the given
+// secrets and configMaps as volumes, so the Node authorizer's ownership
+// graph grants that node's kubelet identity read access to exactly those
+// objects. Referenced Secrets/ConfigMaps are assumed to already exist.
+func CreateNodeBoundPod(c clientset.Interface, namespace, name, node string, secrets, configMaps []string) (*v1.Pod, error) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: traceAnnotations(),
+		},
+		Spec: v1.PodSpec{
+			NodeName: node,
+			Containers: []v1.Container{{
+				Name:  "pause",
+				Image: "k8s.gcr.io/pause:3.1",
+			}},
+		},
+	}
+	for _, secret := range secrets {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name:         "secret-" + secret,
+			VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: secret}},
+		})
+	}
+	for _, configMap := range configMaps {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name:         "configmap-" + configMap,
+			VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: configMap}}},
+		})
+	}
+
+	created, err := c.CoreV1().Pods(namespace).Create(pod)
+	if err != nil {
+		return nil, fmt.Errorf("creating node-bound pod %s/%s on node %q: %v", namespace, name, node, err)
+	}
+	return created, nil
+}
+
+// AssertNodeSecretAccess checks, via SAR, that node's kubelet identity can
+// "get" every secret in allowed and cannot "get" any secret in denied,
+// covering the Node authorizer's ownership-graph scoping in one call instead
+// of one WaitForNamedAuthorizationUpdate per secret at each call site.
+func AssertNodeSecretAccess(c v1beta1authorization.SubjectAccessReviewsGetter, node, namespace string, allowed, denied []string) error {
+	return assertNodeResourceAccess(c, node, namespace, CoreResource("secrets"), allowed, denied)
+}
+
+// AssertNodeConfigMapAccess is the ConfigMap equivalent of AssertNodeSecretAccess.
+func AssertNodeConfigMapAccess(c v1beta1authorization.SubjectAccessReviewsGetter, node, namespace string, allowed, denied []string) error {
+	return assertNodeResourceAccess(c, node, namespace, CoreResource("configmaps"), allowed, denied)
+}
+
+func assertNodeResourceAccess(c v1beta1authorization.SubjectAccessReviewsGetter, node, namespace string, resource schema.GroupResource, allowed, denied []string) error {
+	user := NodeUsername(node)
+	for _, name := range allowed {
+		if err := WaitForNamedAuthorizationUpdate(c, user, namespace, "get", name, resource, true); err != nil {
+			return fmt.Errorf("expected node %q to be allowed to get %s %q: %v", node, resource, name, err)
+		}
+	}
+	for _, name := range denied {
+		if err := WaitForNamedAuthorizationUpdate(c, user, namespace, "get", name, resource, false); err != nil {
+			return fmt.Errorf("expected node %q to be denied getting %s %q: %v", node, resource, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// AssertBindingDoesNotSurviveNamespaceRecreation binds clusterRole to
+// subject in namespace, confirms it grants user access, deletes namespace,
+// waits for it to be fully gone, recreates a namespace with the same name,
+// and asserts user is denied verb on resource there. This guards against
+// UID-vs-name confusion regressions where a deleted namespace's bindings (or
+// their effect in an authorizer's cache) resurface under a new namespace
+// object that merely happens to share its predecessor's name.
+func AssertBindingDoesNotSurviveNamespaceRecreation(nsClient v1core.NamespacesGetter, c bindingsGetter, sarClient v1beta1authorization.SubjectAccessReviewsGetter, namespace, clusterRole string, subject rbacv1beta1.Subject, user, verb string, resource schema.GroupResource) error {
+	if _, err := BindClusterRoleInNamespace(c, clusterRole, namespace, subject); err != nil {
+		return fmt.Errorf("binding clusterrole/%s in %q: %v", clusterRole, namespace, err)
+	}
+	if err := WaitForAuthorizationUpdate(sarClient, user, namespace, verb, resource, true); err != nil {
+		return fmt.Errorf("waiting for the initial binding in %q to propagate: %v", namespace, err)
+	}
+
+	if err := nsClient.Namespaces().Delete(namespace, nil); err != nil {
+		return fmt.Errorf("deleting namespace %q: %v", namespace, err)
+	}
+	if err := waitForNamespaceGone(nsClient, namespace); err != nil {
+		return fmt.Errorf("waiting for namespace %q to be fully deleted: %v", namespace, err)
+	}
+
+	if _, err := nsClient.Namespaces().Create(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}); err != nil {
+		return fmt.Errorf("recreating namespace %q: %v", namespace, err)
+	}
+
+	if err := WaitForAuthorizationUpdate(sarClient, user, namespace, verb, resource, false); err != nil {
+		return fmt.Errorf("asserting the old binding did not resurrect in the recreated %q: %v", namespace, err)
+	}
+	return nil
+}
+
+func waitForNamespaceGone(nsClient v1core.NamespacesGetter, namespace string) error {
+	return wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		_, err := nsClient.Namespaces().Get(namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// OIDCTestIssuer is an in-process OIDC issuer serving a discovery document
+// and JWKS, and minting signed ID tokens on demand. Configuring the
+// apiserver's --oidc-issuer-url/--oidc-client-id/--oidc-username-claim
+// flags to point at it is a cluster bring-up concern out of scope for this
+// package (see TokenAuthenticatorWebhook for the analogous webhook case);
+// this exists to let suites that already run such a cluster mint ID tokens
+// for arbitrary identities at runtime.
+type OIDCTestIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	keyID  string
+}
+
+// NewOIDCTestIssuer starts an in-process OIDC issuer with a freshly
+// generated signing key.
+func NewOIDCTestIssuer() (*OIDCTestIssuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generating OIDC issuer signing key: %v", err)
+	}
+	issuer := &OIDCTestIssuer{key: key, keyID: "auth-e2e-oidc-test-key"}
+	issuer.server = httptest.NewServer(http.HandlerFunc(issuer.serveHTTP))
+	return issuer, nil
+}
+
+// IssuerURL is the value to configure --oidc-issuer-url with.
+func (o *OIDCTestIssuer) IssuerURL() string {
+	return o.server.URL
+}
+
+// Close shuts down the issuer.
+func (o *OIDCTestIssuer) Close() {
+	o.server.Close()
+}
+
+func (o *OIDCTestIssuer) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	switch req.URL.Path {
+	case "/.well-known/openid-configuration":
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"issuer":                                o.server.URL,
+			"jwks_uri":                              o.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	case "/keys":
+		jwk := jose.JSONWebKey{Key: &o.key.PublicKey, KeyID: o.keyID, Algorithm: "RS256", Use: "sig"}
+		json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk}})
+	default:
+		http.NotFound(rw, req)
+	}
+}
+
+// IssueIDToken mints an ID token asserting subject sub (mapped to the
+// authenticated username via the apiserver's --oidc-username-claim, with
+// --oidc-username-prefix applied) and groups (mapped similarly via
+// --oidc-groups-claim/--oidc-groups-prefix), for audience clientID.
+func (o *OIDCTestIssuer) IssueIDToken(clientID, sub string, groups []string) (string, error) {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: o.key}, (&jose.SignerOptions{}).WithHeader("kid", o.keyID))
+	if err != nil {
+		return "", err
+	}
+	claims := map[string]interface{}{
+		"iss":    o.server.URL,
+		"aud":    clientID,
+		"sub":    sub,
+		"groups": groups,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+	}
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+// NewOIDCClient returns a clientset that authenticates using idToken as an
+// OIDC bearer token, exercising whatever OIDC authenticator the apiserver
+// has configured to accept it. The resulting Kubernetes identity, for use
+// with WaitForAuthorizationUpdate and friends, is computed separately by
+// passing the apiserver's --oidc-username-prefix/--oidc-groups-prefix values
+// to OIDCUsername and OIDCGroups; those prefixes have no effect on the token.
+func NewOIDCClient(base *restclient.Config, idToken string) (clientset.Interface, error) {
+	config := restclient.AnonymousClientConfig(base)
+	config.BearerToken = idToken
+	config.UserAgent = IdentityUserAgent(fmt.Sprintf("oidc:%s", idToken))
+	return clientset.NewForConfig(config)
+}
+
+// OIDCUsername applies usernamePrefix to sub the way
+// newAuthenticatorFromOIDCIssuerURL (pkg/kubeapiserver/authenticator) does:
+// an empty prefix defaults to "issuerURL#" for backwards compatibility, and
+// the special value "-" means no prefix at all. The apiserver skips that
+// default when --oidc-username-claim is "email"; this function does not take
+// the claim into account.
+func OIDCUsername(issuerURL, usernamePrefix, sub string) string {
+	if usernamePrefix == "" {
+		usernamePrefix = issuerURL + "#"
+	}
+	if usernamePrefix == "-" {
+		return sub
+	}
+	return usernamePrefix + sub
+}
+
+// OIDCGroups applies groupsPrefix to every group the way the apiserver's
+// OIDC authenticator does.
+func OIDCGroups(groupsPrefix string, groups []string) []string {
+	if groupsPrefix == "" {
+		return groups
+	}
+	prefixed := make([]string, len(groups))
+	for i, g := range groups {
+		prefixed[i] = groupsPrefix + g
+	}
+	return prefixed
+}
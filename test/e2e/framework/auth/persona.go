@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	clientset "k8s.io/client-go/kubernetes"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+	restclient "k8s.io/client-go/rest"
+)
+
+// representativeVerbs is the small verb set persona factories in this file
+// wait to propagate before returning, standing in for "the binding
+// definitely took effect" without waiting on every verb the underlying role
+// grants.
+var representativeVerbs = []string{"get", "list", "create"}
+
+// MakeNamespaceAdmin creates an identity (impersonated as username, which
+// need not exist as a real user) bound to the "admin" ClusterRole scoped to
+// namespace, waits for a representative verb set to propagate, and returns
+// a client authenticating as that identity. This is the most common
+// namespace-scoped persona in multi-tenancy e2e tests.
+func MakeNamespaceAdmin(h *Helper, base *restclient.Config, sarClient v1beta1authorization.SubjectAccessReviewsGetter, username string) (clientset.Interface, error) {
+	if _, err := h.BindClusterRole("admin", rbacv1beta1.Subject{Kind: rbacv1beta1.UserKind, Name: username}); err != nil {
+		return nil, fmt.Errorf("binding admin to %q in %q: %v", username, h.Namespace, err)
+	}
+	for _, verb := range representativeVerbs {
+		if err := WaitForAuthorizationUpdate(sarClient, username, h.Namespace, verb, CoreResource("pods"), true); err != nil {
+			return nil, fmt.Errorf("waiting for admin binding to propagate to %q in %q: %v", username, h.Namespace, err)
+		}
+	}
+	return newImpersonatedClient(base, username, nil)
+}
+
+// MakeClusterViewer creates an identity (impersonated as username) bound to
+// the "view" ClusterRole cluster-wide, waits for a representative verb set
+// to propagate, and returns a client authenticating as that identity, so
+// conformance-style tests asserting read-only behavior share a single
+// implementation. The binding is cleaned up via registerCleanup the same
+// way Helper cleans up its own bindings (pass nil to skip).
+func MakeClusterViewer(c bindingsGetter, base *restclient.Config, sarClient v1beta1authorization.SubjectAccessReviewsGetter, registerCleanup func(func()), username string) (clientset.Interface, error) {
+	binding, err := BindClusterRole(c, "view", "cluster-viewer--"+username, rbacv1beta1.Subject{Kind: rbacv1beta1.UserKind, Name: username})
+	if err != nil {
+		return nil, fmt.Errorf("binding view to %q cluster-wide: %v", username, err)
+	}
+	if binding != nil && registerCleanup != nil {
+		registerCleanup(func() {
+			if err := c.ClusterRoleBindings().Delete(binding.Name, nil); err != nil {
+				logf("WARNING: failed to clean up clusterrolebinding/%s: %v", binding.Name, err)
+				return
+			}
+			recordObjectCleaned()
+		})
+	}
+	for _, verb := range representativeVerbs {
+		if verb == "create" {
+			// "view" is read-only; a representative write verb would always
+			// (correctly) fail, so only wait on the read verbs for this persona.
+			continue
+		}
+		if err := WaitForClusterWideAuthorizationUpdate(sarClient, username, verb, CoreResource("pods"), true); err != nil {
+			return nil, fmt.Errorf("waiting for view binding to propagate to %q: %v", username, err)
+		}
+	}
+	return newImpersonatedClient(base, username, nil)
+}
+
+func newImpersonatedClient(base *restclient.Config, username string, groups []string) (clientset.Interface, error) {
+	config := restclient.CopyConfig(base)
+	config.Impersonate = restclient.ImpersonationConfig{UserName: username, Groups: groups}
+	config.UserAgent = IdentityUserAgent(username)
+	return clientset.NewForConfig(config)
+}
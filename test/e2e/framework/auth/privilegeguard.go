@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"github.com/pkg/errors"
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+)
+
+// PrivilegedClusterRoles are ClusterRoles that grant effectively unrestricted
+// cluster-wide access. BindClusterRole refuses to bind one of these, or to
+// bind anything to the GroupMasters subject, unless the caller has opted in
+// via AllowPrivilegedBindings.
+var PrivilegedClusterRoles = map[string]bool{
+	"cluster-admin": true,
+}
+
+// allowPrivilegedBindings gates the privileged-binding refusal below. It
+// defaults to false so that a test snippet copy-pasted from an example that
+// happened to use cluster-admin doesn't silently hand out cluster-admin on
+// whatever shared cluster it's run against next.
+var allowPrivilegedBindings bool
+
+// AllowPrivilegedBindings opts the calling suite into BindClusterRole
+// creating bindings to PrivilegedClusterRoles or to the GroupMasters subject.
+// Suites that intentionally exercise cluster-admin-level access should call
+// this once (e.g. in a BeforeEach) and restore it to false afterwards.
+func AllowPrivilegedBindings(allow bool) {
+	allowPrivilegedBindings = allow
+}
+
+// checkPrivilegeGuard refuses a clusterRole/subjects combination that would
+// grant cluster-admin-equivalent access unless the caller opted in via
+// AllowPrivilegedBindings.
+func checkPrivilegeGuard(clusterRole string, subjects []rbacv1beta1.Subject) error {
+	if allowPrivilegedBindings {
+		return nil
+	}
+	if PrivilegedClusterRoles[clusterRole] {
+		return errors.Errorf("refusing to bind privileged clusterrole/%s without AllowPrivilegedBindings(true)", clusterRole)
+	}
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1beta1.GroupKind && subject.Name == GroupMasters {
+			return errors.Errorf("refusing to bind clusterrole/%s to group %q without AllowPrivilegedBindings(true)", clusterRole, GroupMasters)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"strings"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// SubjectAccessReviewExpectation describes what a WaitForSubjectAccessReview
+// caller expects the review to converge on.
+type SubjectAccessReviewExpectation struct {
+	Allowed bool
+	// ReasonSubstring, if non-empty, must appear in Status.Reason.
+	ReasonSubstring string
+}
+
+// WaitForSubjectAccessReview polls spec against c until the response matches
+// expected, for attributes (non-resource URLs, extra fields, arbitrary
+// group/version combinations) that WaitForAuthorizationUpdate and its
+// siblings don't expose parameters for.
+func WaitForSubjectAccessReview(c v1beta1authorization.SubjectAccessReviewsGetter, spec authorizationv1beta1.SubjectAccessReviewSpec, expected SubjectAccessReviewExpectation) error {
+	review := &authorizationv1beta1.SubjectAccessReview{Spec: spec}
+
+	start := time.Now()
+	var lastResponse *authorizationv1beta1.SubjectAccessReview
+	var lastErr error
+	polls := 0
+	err := wait.Poll(policyCachePollInterval, cacheAwarePollTimeout(expected.Allowed), func() (bool, error) {
+		polls++
+		response, err := c.SubjectAccessReviews().Create(review)
+		if apierrors.IsNotFound(err) {
+			logf("SubjectAccessReview endpoint is missing")
+			time.Sleep(1 * time.Second)
+			return true, nil
+		}
+		if err != nil {
+			lastErr = err
+			return false, err
+		}
+		lastResponse = response
+		if response.Status.Allowed != expected.Allowed {
+			return false, nil
+		}
+		if expected.ReasonSubstring != "" && !strings.Contains(response.Status.Reason, expected.ReasonSubstring) {
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return reportAuthorizationFailure(spec, expected.Allowed, lastResponse, time.Since(start), polls, lastErr)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+)
+
+// FormatSubject renders a single RBAC subject as "Kind/name" (or
+// "Kind/apiGroup/name" for a subject with a non-empty APIGroup), which is
+// what shows up in `kubectl describe`, instead of the Go-struct dump that
+// %v produces for rbacv1beta1.Subject.
+func FormatSubject(subject rbacv1beta1.Subject) string {
+	if subject.APIGroup == "" {
+		return fmt.Sprintf("%s/%s", subject.Kind, subject.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", subject.Kind, subject.APIGroup, subject.Name)
+}
+
+// FormatSubjects renders subjects as a comma-separated list of FormatSubject
+// results, or "<none>" for an empty/nil slice.
+func FormatSubjects(subjects []rbacv1beta1.Subject) string {
+	if len(subjects) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		parts = append(parts, FormatSubject(subject))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatRoleRef renders a RoleRef as "Kind/name".
+func FormatRoleRef(ref rbacv1beta1.RoleRef) string {
+	return fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+}
+
+// FormatPolicyRule renders one PolicyRule as a compact
+// "verbs on apiGroups/resources[/subresources] (resourceNames)" line.
+func FormatPolicyRule(rule rbacv1beta1.PolicyRule) string {
+	line := fmt.Sprintf("%s on %s/%s",
+		strings.Join(rule.Verbs, ","),
+		strings.Join(orNone(rule.APIGroups), ","),
+		strings.Join(orNone(rule.Resources), ","))
+	if len(rule.ResourceNames) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(rule.ResourceNames, ","))
+	}
+	if len(rule.NonResourceURLs) > 0 {
+		line += fmt.Sprintf(" nonResourceURLs=%s", strings.Join(rule.NonResourceURLs, ","))
+	}
+	return line
+}
+
+// FormatPolicyRules renders rules as one FormatPolicyRule line per rule,
+// joined with "; ".
+func FormatPolicyRules(rules []rbacv1beta1.PolicyRule) string {
+	if len(rules) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		parts = append(parts, FormatPolicyRule(rule))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FormatBinding renders a roleRef+subjects pair as it would read in a
+// failure message: "RoleRef/name -> Subject/a, Subject/b". Both
+// ClusterRoleBinding and RoleBinding share this shape.
+func FormatBinding(roleRef rbacv1beta1.RoleRef, subjects []rbacv1beta1.Subject) string {
+	return fmt.Sprintf("%s -> %s", FormatRoleRef(roleRef), FormatSubjects(subjects))
+}
+
+func orNone(values []string) []string {
+	if len(values) == 0 {
+		return []string{"*"}
+	}
+	return values
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForNamedAuthorizationUpdateWithReason behaves like
+// WaitForNamedAuthorizationUpdate, but additionally requires the SAR
+// response's Status.Reason to contain wantReasonSubstring (ignored if empty),
+// and fails immediately -- without waiting out the poll timeout -- if
+// Status.EvaluationError is ever set, since a webhook authorizer that errors
+// during evaluation will not converge on retry the way an unpropagated
+// policy change would.
+func WaitForNamedAuthorizationUpdateWithReason(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool, wantReasonSubstring string) error {
+	review := &authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Group:     resource.Group,
+				Verb:      verb,
+				Resource:  resource.Resource,
+				Namespace: namespace,
+				Name:      resourceName,
+			},
+			User: user,
+		},
+	}
+
+	start := time.Now()
+	var lastResponse *authorizationv1beta1.SubjectAccessReview
+	var lastErr error
+	polls := 0
+	err := wait.Poll(policyCachePollInterval, cacheAwarePollTimeout(allowed), func() (bool, error) {
+		polls++
+		response, err := c.SubjectAccessReviews().Create(review)
+		if apierrors.IsNotFound(err) {
+			logf("SubjectAccessReview endpoint is missing")
+			time.Sleep(1 * time.Second)
+			return true, nil
+		}
+		if err != nil {
+			lastErr = err
+			return false, err
+		}
+		lastResponse = response
+
+		if response.Status.EvaluationError != "" {
+			lastErr = fmt.Errorf("authorizer reported an evaluation error: %s", response.Status.EvaluationError)
+			return false, lastErr
+		}
+		if response.Status.Allowed != allowed {
+			return false, nil
+		}
+		if wantReasonSubstring != "" && !strings.Contains(response.Status.Reason, wantReasonSubstring) {
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return reportAuthorizationFailure(review.Spec, allowed, lastResponse, time.Since(start), polls, lastErr)
+	}
+	return nil
+}
+
+// WaitForClusterWideAuthorizationUpdateWithReason is the cluster-wide
+// counterpart of WaitForNamedAuthorizationUpdateWithReason, for asserting
+// that a cluster-wide grant came from the expected binding (e.g. by name)
+// rather than a leftover policy object that happens to also allow it.
+func WaitForClusterWideAuthorizationUpdateWithReason(c v1beta1authorization.SubjectAccessReviewsGetter, user, verb string, resource schema.GroupResource, allowed bool, wantReasonSubstring string) error {
+	return WaitForNamedAuthorizationUpdateWithReason(c, user, "", verb, "", resource, allowed, wantReasonSubstring)
+}
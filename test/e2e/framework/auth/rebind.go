@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RebindRole changes the RoleRef a ClusterRoleBinding grants. RoleRef is
+// immutable once a binding is created, so this performs the required
+// delete-then-recreate itself, preserving the binding's subjects, and calls
+// waitForOldGone (if non-nil) after deleting the old binding and
+// waitForNewGranted (if non-nil) after creating the new one -- typically
+// closures around WaitForAuthorizationUpdate -- so callers observe a single
+// atomic-looking operation instead of hand-rolling the delete/recreate/wait
+// dance at every call site.
+func RebindRole(c bindingsGetter, bindingName string, newRoleRef rbacv1beta1.RoleRef, waitForOldGone, waitForNewGranted func() error) error {
+	existing, err := c.ClusterRoleBindings().Get(bindingName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("looking up clusterrolebinding/%s to rebind: %v", bindingName, err)
+	}
+
+	if err := c.ClusterRoleBindings().Delete(bindingName, nil); err != nil {
+		return fmt.Errorf("deleting clusterrolebinding/%s before rebind: %v", bindingName, err)
+	}
+	if waitForOldGone != nil {
+		if err := waitForOldGone(); err != nil {
+			return fmt.Errorf("waiting for old permissions from clusterrolebinding/%s to disappear: %v", bindingName, err)
+		}
+	}
+
+	_, err = c.ClusterRoleBindings().Create(&rbacv1beta1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        bindingName,
+			Annotations: traceAnnotations(),
+		},
+		RoleRef:  newRoleRef,
+		Subjects: existing.Subjects,
+	})
+	if err != nil {
+		return fmt.Errorf("recreating clusterrolebinding/%s with new roleRef %+v: %v", bindingName, newRoleRef, err)
+	}
+	if waitForNewGranted != nil {
+		if err := waitForNewGranted(); err != nil {
+			return fmt.Errorf("waiting for new permissions from clusterrolebinding/%s to appear: %v", bindingName, err)
+		}
+	}
+	return nil
+}
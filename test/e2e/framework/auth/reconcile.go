@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"reflect"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// IsAutoUpdateProtected reports whether role has been marked to opt out of
+// bootstrap policy reconciliation, i.e. its
+// rbac.authorization.kubernetes.io/autoupdate annotation is explicitly
+// "false".
+func IsAutoUpdateProtected(role *rbacv1beta1.ClusterRole) bool {
+	return role.Annotations[rbacv1beta1.AutoUpdateAnnotationKey] == "false"
+}
+
+// MutateDefaultClusterRole intentionally adds an extra PolicyRule to the
+// named default (bootstrap) ClusterRole, for tests that then assert the
+// bootstrap policy reconciler reverts the change. It fails if the role is
+// annotated to opt out of reconciliation, since mutating it would leave the
+// mutation in place and give a false pass.
+func MutateDefaultClusterRole(c v1beta1rbac.ClusterRolesGetter, name string, extraRule rbacv1beta1.PolicyRule) error {
+	role, err := c.ClusterRoles().Get(name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("getting default clusterrole/%s: %v", name, err)
+	}
+	if IsAutoUpdateProtected(role) {
+		return fmt.Errorf("clusterrole/%s is annotated %s=false; mutating it would not exercise reconciliation", name, rbacv1beta1.AutoUpdateAnnotationKey)
+	}
+
+	role.Rules = append(role.Rules, extraRule)
+	if _, err := c.ClusterRoles().Update(role); err != nil {
+		return fmt.Errorf("mutating default clusterrole/%s: %v", name, err)
+	}
+	return nil
+}
+
+// WaitForClusterRoleReconciliation polls until the named ClusterRole's Rules
+// no longer contain unwantedRule, i.e. the bootstrap policy reconciler has
+// restored it to its shipped defaults.
+func WaitForClusterRoleReconciliation(c v1beta1rbac.ClusterRolesGetter, name string, unwantedRule rbacv1beta1.PolicyRule) error {
+	err := wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		role, err := c.ClusterRoles().Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, rule := range role.Rules {
+			if reflect.DeepEqual(rule, unwantedRule) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("clusterrole/%s was not reconciled away from %+v within %s: %v", name, unwantedRule, policyCachePollTimeout, err)
+	}
+	return nil
+}
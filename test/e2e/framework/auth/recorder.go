@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+)
+
+// RecordedSAR is one SubjectAccessReview this package sent and the response
+// (if any) it got back, captured for offline analysis of a flaky
+// authorization wait or replay against a fake authorizer.
+type RecordedSAR struct {
+	Time   time.Time                                      `json:"time"`
+	Spec   authorizationv1beta1.SubjectAccessReviewSpec   `json:"spec"`
+	Status authorizationv1beta1.SubjectAccessReviewStatus `json:"status"`
+	Error  string                                         `json:"error,omitempty"`
+}
+
+var (
+	sarRecorderMu sync.Mutex
+	sarRecording  bool
+	sarRecords    []RecordedSAR
+)
+
+// EnableSARRecording turns on capture of every SubjectAccessReview
+// WaitForNamedAuthorizationUpdate (and the wait helpers built on it) sends,
+// and the responses received, for the remainder of the process. SARs sent
+// by this package's other helpers are not recorded. Off by default:
+// recording every SAR is only
+// useful while actively debugging a flake, not on every run.
+func EnableSARRecording() {
+	sarRecorderMu.Lock()
+	defer sarRecorderMu.Unlock()
+	sarRecording = true
+}
+
+// DisableSARRecording turns off recording, without clearing what's already
+// been captured.
+func DisableSARRecording() {
+	sarRecorderMu.Lock()
+	defer sarRecorderMu.Unlock()
+	sarRecording = false
+}
+
+// RecordedSARs returns a copy of every SAR captured so far.
+func RecordedSARs() []RecordedSAR {
+	sarRecorderMu.Lock()
+	defer sarRecorderMu.Unlock()
+	out := make([]RecordedSAR, len(sarRecords))
+	copy(out, sarRecords)
+	return out
+}
+
+// DumpRecordedSARs renders RecordedSARs as an indented JSON artifact.
+func DumpRecordedSARs() ([]byte, error) {
+	return json.MarshalIndent(RecordedSARs(), "", "  ")
+}
+
+// ResetSARRecording discards everything captured so far without changing
+// whether recording is enabled.
+func ResetSARRecording() {
+	sarRecorderMu.Lock()
+	defer sarRecorderMu.Unlock()
+	sarRecords = nil
+}
+
+func recordSAR(spec authorizationv1beta1.SubjectAccessReviewSpec, response *authorizationv1beta1.SubjectAccessReview, err error) {
+	sarRecorderMu.Lock()
+	defer sarRecorderMu.Unlock()
+	if !sarRecording {
+		return
+	}
+	record := RecordedSAR{Time: time.Now(), Spec: spec}
+	if response != nil {
+		record.Status = response.Status
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	sarRecords = append(sarRecords, record)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// NewRefreshingServiceAccountClient returns a clientset authenticating as
+// namespace/name using short-lived (ttl) ServiceAccount tokens minted via
+// TokenRequest, refreshed automatically shortly before each one expires, so
+// a long-running spec exercising a short TTL on purpose doesn't start
+// failing with 401 partway through simply because the token it grabbed once
+// at setup time aged out.
+func NewRefreshingServiceAccountClient(base *restclient.Config, tokens clientset.Interface, namespace, name string, ttl time.Duration) (clientset.Interface, error) {
+	rt := &refreshingTokenRoundTripper{
+		tokens:    tokens,
+		namespace: namespace,
+		name:      name,
+		ttl:       ttl,
+	}
+	if err := rt.refresh(); err != nil {
+		return nil, fmt.Errorf("minting initial token for serviceaccount %s/%s: %v", namespace, name, err)
+	}
+
+	config := restclient.AnonymousClientConfig(base)
+	config.UserAgent = IdentityUserAgent("system:serviceaccount:" + namespace + ":" + name)
+	config.WrapTransport = func(inner http.RoundTripper) http.RoundTripper {
+		rt.inner = inner
+		return rt
+	}
+	return clientset.NewForConfig(config)
+}
+
+// refreshingTokenRoundTripper stamps the current token onto every outgoing
+// request, minting a replacement shortly before expiry.
+type refreshingTokenRoundTripper struct {
+	inner     http.RoundTripper
+	tokens    clientset.Interface
+	namespace string
+	name      string
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (rt *refreshingTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := rt.currentToken()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return rt.inner.RoundTrip(req)
+}
+
+func (rt *refreshingTokenRoundTripper) currentToken() (string, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	// Refresh once we're within a quarter of the TTL of expiring, rather than
+	// waiting for outright expiry, so an in-flight request never races a
+	// token that's about to be rejected.
+	if time.Now().Add(rt.ttl / 4).Before(rt.expiresAt) {
+		return rt.token, nil
+	}
+	if err := rt.refreshLocked(); err != nil {
+		return "", err
+	}
+	return rt.token, nil
+}
+
+func (rt *refreshingTokenRoundTripper) refresh() error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.refreshLocked()
+}
+
+func (rt *refreshingTokenRoundTripper) refreshLocked() error {
+	seconds := int64(rt.ttl.Seconds())
+	tr, err := rt.tokens.CoreV1().ServiceAccounts(rt.namespace).CreateToken(rt.name, &authenticationv1.TokenRequest{
+		ObjectMeta: metav1.ObjectMeta{Annotations: traceAnnotations()},
+		Spec:       authenticationv1.TokenRequestSpec{ExpirationSeconds: &seconds},
+	})
+	if err != nil {
+		return err
+	}
+	rt.token = tr.Status.Token
+	rt.expiresAt = tr.Status.ExpirationTimestamp.Time
+	return nil
+}
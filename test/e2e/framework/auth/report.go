@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+)
+
+// authorizationFailureRecord is a machine-readable summary of a failed
+// authorization wait: what was expected, what the last SubjectAccessReview
+// actually returned, and how long the wait ran before giving up. It is
+// logged to the GinkgoWriter, which the e2e JUnit reporter captures as the
+// failing spec's system-out, so authz flakes are triageable from test-grid
+// artifacts alone without re-running with verbose logging.
+type authorizationFailureRecord struct {
+	ExpectedAllowed bool                                            `json:"expectedAllowed"`
+	Attributes      *authorizationv1beta1.ResourceAttributes        `json:"attributes"`
+	User            string                                          `json:"user"`
+	LastStatus      *authorizationv1beta1.SubjectAccessReviewStatus `json:"lastStatus,omitempty"`
+	Elapsed         time.Duration                                   `json:"elapsedNanos"`
+	Polls           int                                             `json:"polls"`
+}
+
+// reportAuthorizationFailure both logs the machine-readable failure record
+// described above and returns a human-readable error carrying the same
+// information, so a CI failure message is self-explanatory from the error
+// text alone without anyone needing to go dig up the AUTHORIZATION_WAIT_FAILURE
+// log line it also emits. lastErr is the last non-nil error the poll loop
+// saw from the SubjectAccessReview call itself (as opposed to lastResponse,
+// a successful response whose Status just didn't match yet); at most one of
+// lastResponse and lastErr is meaningful for a given failure.
+func reportAuthorizationFailure(spec authorizationv1beta1.SubjectAccessReviewSpec, expectedAllowed bool, lastResponse *authorizationv1beta1.SubjectAccessReview, elapsed time.Duration, polls int, lastErr error) error {
+	record := authorizationFailureRecord{
+		ExpectedAllowed: expectedAllowed,
+		Attributes:      spec.ResourceAttributes,
+		User:            spec.User,
+		Elapsed:         elapsed,
+		Polls:           polls,
+	}
+	if lastResponse != nil {
+		record.LastStatus = &lastResponse.Status
+	}
+
+	if data, err := json.Marshal(record); err != nil {
+		logf("failed to marshal authorization failure record: %v", err)
+	} else {
+		fmt.Fprintf(ginkgo.GinkgoWriter, "AUTHORIZATION_WAIT_FAILURE %s\n", data)
+	}
+
+	lastStatus := "no SubjectAccessReview response was ever received"
+	if lastResponse != nil {
+		lastStatus = fmt.Sprintf("last status: allowed=%v reason=%q evaluationError=%q",
+			lastResponse.Status.Allowed, lastResponse.Status.Reason, lastResponse.Status.EvaluationError)
+	}
+	target := "(no request attributes"
+	if ra := spec.ResourceAttributes; ra != nil {
+		target = fmt.Sprintf("%s %s/%s (namespace %q", ra.Verb, ra.Group, ra.Resource, ra.Namespace)
+	} else if nra := spec.NonResourceAttributes; nra != nil {
+		target = fmt.Sprintf("%s %s (non-resource", nra.Verb, nra.Path)
+	}
+	err := fmt.Errorf("waiting for %s to %s, want allowed=%v) after %d polls over %v: %s",
+		spec.User, target, expectedAllowed, polls, elapsed, lastStatus)
+	if lastErr != nil {
+		err = fmt.Errorf("%v; last poll error: %v", err, lastErr)
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// requestInfoFactory mirrors how the apiserver itself is wired
+// (k8s.io/apiserver/pkg/server's NewRequestInfoResolver), so the verb and
+// resource/subresource this package derives from a described request match
+// what the real apiserver would derive from the same request.
+var requestInfoFactory = &apirequest.RequestInfoFactory{
+	APIPrefixes:          sets.NewString("api", "apis"),
+	GrouplessAPIPrefixes: sets.NewString("api"),
+}
+
+// ResourceAttributesForRequest translates a described REST request (method
+// and URL path) into the authorizationv1beta1.ResourceAttributes an
+// apiserver would evaluate it as, using the same request-info parsing the
+// apiserver itself uses, so tests can assert "this concrete request is
+// authorized as X" without hand-translating HTTP verbs and REST paths into
+// RBAC verbs themselves.
+//
+// It only handles resource requests; non-resource paths like /healthz have
+// no ResourceAttributes representation and return an error.
+func ResourceAttributesForRequest(method, path string) (*authorizationv1beta1.ResourceAttributes, error) {
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for %s %s: %v", method, path, err)
+	}
+
+	info, err := requestInfoFactory.NewRequestInfo(req)
+	if err != nil {
+		return nil, fmt.Errorf("parsing request info for %s %s: %v", method, path, err)
+	}
+	if !info.IsResourceRequest {
+		return nil, fmt.Errorf("%s %s is not a resource request; it has no ResourceAttributes representation", method, path)
+	}
+
+	return &authorizationv1beta1.ResourceAttributes{
+		Namespace:   info.Namespace,
+		Verb:        info.Verb,
+		Group:       info.APIGroup,
+		Version:     info.APIVersion,
+		Resource:    info.Resource,
+		Subresource: info.Subresource,
+		Name:        info.Name,
+	}, nil
+}
+
+// WaitForRequestAuthorizationUpdate waits for the authorization decision for
+// a described concrete request (as ResourceAttributesForRequest would
+// resolve it) to reach allowed, without the caller manually translating the
+// request into a verb and resource first.
+func WaitForRequestAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, user, method, path string, allowed bool) error {
+	attrs, err := ResourceAttributesForRequest(method, path)
+	if err != nil {
+		return err
+	}
+
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource = resource + "/" + attrs.Subresource
+	}
+	gvr := schema.GroupVersionResource{Group: attrs.Group, Version: attrs.Version, Resource: resource}
+	return WaitForNamedAuthorizationUpdateWithVersion(c, user, attrs.Namespace, attrs.Verb, attrs.Name, gvr, allowed)
+}
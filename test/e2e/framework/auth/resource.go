@@ -0,0 +1,38 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+// CoreResource returns the schema.GroupResource for a core/legacy API group
+// resource, e.g. CoreResource("pods").
+func CoreResource(resource string) schema.GroupResource {
+	return schema.GroupResource{Resource: resource}
+}
+
+// Resource returns the schema.GroupResource for resource in group, e.g.
+// Resource("apps", "deployments").
+func Resource(group, resource string) schema.GroupResource {
+	return schema.GroupResource{Group: group, Resource: resource}
+}
+
+// SubResource returns the "<resource>/<subresource>" GroupResource used by
+// the wait helpers to check subresource permissions, e.g.
+// SubResource("", "pods", "log").
+func SubResource(group, resource, subresource string) schema.GroupResource {
+	return schema.GroupResource{Group: group, Resource: resource + "/" + subresource}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// BindClusterRoleToNamespaceServiceAccounts binds clusterRole to
+// system:serviceaccounts:<saNamespace>, the group every service account in
+// saNamespace is implicitly a member of, at the cluster scope. Combined with
+// WaitForServiceAccountGroupAuthorizationUpdate, this exercises the
+// authorizer's group-membership matching for a real ServiceAccount subject
+// rather than the direct-subject matching every other Bind* helper checks.
+func BindClusterRoleToNamespaceServiceAccounts(c bindingsGetter, clusterRole, saNamespace, ns string) (cleanup func(), err error) {
+	return BindClusterRoleToGroup(c, clusterRole, "system:serviceaccounts:"+saNamespace, ns)
+}
+
+// WaitForServiceAccountGroupAuthorizationUpdate waits for the named
+// ServiceAccount to be authorized (or not) via its group membership rather
+// than a binding naming it directly, i.e. via a binding to
+// system:serviceaccounts or system:serviceaccounts:<namespace>.
+func WaitForServiceAccountGroupAuthorizationUpdate(c v1beta1authorization.SubjectAccessReviewsGetter, saNamespace, saName, namespace, verb string, resource schema.GroupResource, allowed bool) error {
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", saNamespace, saName)
+	if err := WaitForAuthorizationUpdate(c, user, namespace, verb, resource, allowed); err != nil {
+		return fmt.Errorf("waiting for group-derived authorization update for %s: %v", user, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForServiceAccountTokenSecret polls the named ServiceAccount until the
+// token controller has populated it with at least one legacy token Secret,
+// so callers don't race that controller when they need a usable credential
+// immediately after creating the ServiceAccount.
+func WaitForServiceAccountTokenSecret(c clientset.Interface, namespace, name string) error {
+	return wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		sa, err := c.CoreV1().ServiceAccounts(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return len(sa.Secrets) > 0, nil
+	})
+}
+
+// CreateServiceAccountAndBind creates a ServiceAccount, waits for the token
+// controller to give it a usable legacy token Secret, binds clusterRole to
+// it at namespace scope, and waits for the binding to propagate -- the
+// combined operation e2e tests otherwise assemble by hand from three
+// separately-raced steps.
+func CreateServiceAccountAndBind(c clientset.Interface, sarClient v1beta1authorization.SubjectAccessReviewsGetter, namespace, name, clusterRole, verb string, resource schema.GroupResource) error {
+	if _, err := c.CoreV1().ServiceAccounts(namespace).Create(&v1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}); err != nil {
+		return fmt.Errorf("creating serviceaccount %s/%s: %v", namespace, name, err)
+	}
+
+	if err := WaitForServiceAccountTokenSecret(c, namespace, name); err != nil {
+		return fmt.Errorf("waiting for serviceaccount %s/%s token secret: %v", namespace, name, err)
+	}
+
+	subject := rbacv1beta1.Subject{Kind: rbacv1beta1.ServiceAccountKind, Namespace: namespace, Name: name}
+	if _, err := BindClusterRoleInNamespace(c.RbacV1beta1(), clusterRole, namespace, subject); err != nil {
+		return fmt.Errorf("binding clusterrole/%s to serviceaccount %s/%s: %v", clusterRole, namespace, name, err)
+	}
+
+	user := "system:serviceaccount:" + namespace + ":" + name
+	if err := WaitForAuthorizationUpdate(sarClient, user, namespace, verb, resource, true); err != nil {
+		return fmt.Errorf("waiting for clusterrole/%s to propagate to serviceaccount %s/%s: %v", clusterRole, namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// ScenarioBinding is one binding RunScenario creates before checking
+// expectations. An empty Namespace means a cluster-scoped ClusterRoleBinding;
+// a non-empty one means a namespaced RoleBinding to the same ClusterRole.
+type ScenarioBinding struct {
+	ClusterRole string
+	Namespace   string
+	Subjects    []rbacv1beta1.Subject
+}
+
+// ScenarioExpectation is one access check RunScenario waits to converge on
+// after setting up a Scenario's bindings.
+type ScenarioExpectation struct {
+	User      string
+	Namespace string
+	Verb      string
+	Resource  schema.GroupResource
+	Allowed   bool
+}
+
+// Scenario declares an RBAC test case as data: bindings to set up, then
+// access expectations to assert once they've propagated. Name is used only
+// to derive unique binding names and to prefix error messages so a failure
+// in one table-driven scenario is distinguishable from another's.
+type Scenario struct {
+	Name         string
+	Bindings     []ScenarioBinding
+	Expectations []ScenarioExpectation
+}
+
+// RunScenario creates s's bindings, waits for each of s's expectations to
+// converge, and returns the first error encountered from either step. It
+// always cleans up everything it created, in reverse creation order,
+// regardless of outcome, so a failed scenario doesn't leak bindings into the
+// ones that run after it.
+func RunScenario(c bindingsGetter, sarClient v1beta1authorization.SubjectAccessReviewsGetter, s Scenario) error {
+	var cleanups []func()
+	defer func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}()
+
+	for _, binding := range s.Bindings {
+		if binding.Namespace == "" {
+			created, err := BindClusterRole(c, binding.ClusterRole, s.Name, binding.Subjects...)
+			if err != nil {
+				return fmt.Errorf("scenario %q: binding clusterrole/%s cluster-wide: %v", s.Name, binding.ClusterRole, err)
+			}
+			if created != nil {
+				name := created.Name
+				cleanups = append(cleanups, func() {
+					if err := c.ClusterRoleBindings().Delete(name, nil); err != nil {
+						logf("WARNING: scenario %q: failed to clean up clusterrolebinding/%s: %v", s.Name, name, err)
+						return
+					}
+					recordObjectCleaned()
+				})
+			}
+			continue
+		}
+
+		created, err := BindClusterRoleInNamespace(c, binding.ClusterRole, binding.Namespace, binding.Subjects...)
+		if err != nil {
+			return fmt.Errorf("scenario %q: binding clusterrole/%s in %q: %v", s.Name, binding.ClusterRole, binding.Namespace, err)
+		}
+		if created != nil {
+			namespace, name := created.Namespace, created.Name
+			cleanups = append(cleanups, func() {
+				if err := c.RoleBindings(namespace).Delete(name, nil); err != nil {
+					logf("WARNING: scenario %q: failed to clean up rolebinding/%s in %q: %v", s.Name, name, namespace, err)
+					return
+				}
+				recordObjectCleaned()
+			})
+		}
+	}
+
+	for _, exp := range s.Expectations {
+		if err := WaitForAuthorizationUpdate(sarClient, exp.User, exp.Namespace, exp.Verb, exp.Resource, exp.Allowed); err != nil {
+			return fmt.Errorf("scenario %q: expectation (%s %s %s in %q, want allowed=%v) failed: %v",
+				s.Name, exp.User, exp.Verb, exp.Resource, exp.Namespace, exp.Allowed, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Helper is bound to a single client and namespace so call sites don't
+// repeat them on every Bind* call. Objects it creates are removed via
+// registerCleanup, riding whatever cleanup lifecycle the caller's framework
+// uses (e.g. framework.AddCleanupAction), instead of requiring every spec to
+// remember its own teardown.
+//
+// This package cannot import test/e2e/framework directly (framework already
+// imports this package for IsRBACEnabled/BindClusterRoleInNamespace), so
+// ForFramework takes the client, namespace and cleanup registration
+// function a *framework.Framework carries rather than the framework type
+// itself; call it as auth.ForFramework(f.ClientSet, f.Namespace.Name, framework.AddCleanupAction).
+type Helper struct {
+	Client          clientset.Interface
+	Namespace       string
+	registerCleanup func(func())
+}
+
+// ForFramework returns a Helper pre-bound to client and namespace.
+func ForFramework(client clientset.Interface, namespace string, registerCleanup func(func())) *Helper {
+	return &Helper{Client: client, Namespace: namespace, registerCleanup: registerCleanup}
+}
+
+// BindRole binds role at namespace scope to subjects, as BindRoleInNamespace
+// does, and schedules the binding's removal on the framework's cleanup
+// lifecycle.
+func (h *Helper) BindRole(role string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, error) {
+	binding, err := BindRoleInNamespace(h.Client.RbacV1beta1(), role, h.Namespace, subjects...)
+	if err != nil {
+		return nil, err
+	}
+	h.scheduleNamespacedCleanup(role)
+	return binding, nil
+}
+
+// BindClusterRole binds clusterRole at namespace scope to subjects, as
+// BindClusterRoleInNamespace does, and schedules the binding's removal on
+// the framework's cleanup lifecycle.
+func (h *Helper) BindClusterRole(clusterRole string, subjects ...rbacv1beta1.Subject) (*rbacv1beta1.RoleBinding, error) {
+	binding, err := BindClusterRoleInNamespace(h.Client.RbacV1beta1(), clusterRole, h.Namespace, subjects...)
+	if err != nil {
+		return nil, err
+	}
+	h.scheduleNamespacedCleanup(clusterRole)
+	return binding, nil
+}
+
+func (h *Helper) scheduleNamespacedCleanup(role string) {
+	if h.registerCleanup == nil {
+		return
+	}
+	bindingName := h.Namespace + "--" + role
+	h.registerCleanup(func() {
+		if err := h.Client.RbacV1beta1().RoleBindings(h.Namespace).Delete(bindingName, nil); err != nil {
+			logf("WARNING: failed to clean up rolebinding/%s in %q: %v", bindingName, h.Namespace, err)
+			return
+		}
+		recordObjectCleaned()
+	})
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo"
+
+	v1beta1authorization "k8s.io/api/authorization/v1beta1"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// SkipUnlessRBACEnabled skips the current spec unless c reports RBAC is
+// enabled, so RBAC-specific specs fail fast with an actionable message
+// instead of silently no-oping every Bind* call and failing later on an
+// unrelated authorization assertion.
+func SkipUnlessRBACEnabled(c v1beta1rbac.ClusterRolesGetter) {
+	if !IsRBACEnabled(c) {
+		ginkgo.Skip("skipping test, which requires RBAC, since it is not enabled")
+	}
+}
+
+// SkipUnlessSARAvailable skips the current spec unless the given client can
+// create SubjectAccessReviews, so specs built on WaitForAuthorizationUpdate
+// and friends fail fast with an actionable message instead of the opaque
+// poll timeout that not having the SAR API produces.
+func SkipUnlessSARAvailable(c authorizationclient.SubjectAccessReviewsGetter) {
+	_, err := c.SubjectAccessReviews().Create(&v1beta1authorization.SubjectAccessReview{
+		Spec: v1beta1authorization.SubjectAccessReviewSpec{
+			ResourceAttributes: &v1beta1authorization.ResourceAttributes{
+				Verb:     "get",
+				Resource: "namespaces",
+			},
+			User: "system:anonymous",
+		},
+	})
+	if err != nil {
+		ginkgo.Skip(fmt.Sprintf("skipping test, which requires the SubjectAccessReview API: %v", err))
+	}
+}
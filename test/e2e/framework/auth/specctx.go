@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForAuthorizationUpdateWithContext behaves like
+// WaitForNamedAuthorizationUpdate but polls until ctx is done instead of a
+// fixed policyCachePollTimeout, so a caller-supplied deadline or
+// cancellation (including, once this repository moves to ginkgo v2, a
+// SpecContext honoring spec timeouts and interrupts -- SpecContext embeds
+// context.Context, so it satisfies this signature without any ginkgo v2
+// dependency here) ends the wait promptly instead of on the package's own
+// clock.
+func WaitForAuthorizationUpdateWithContext(ctx context.Context, c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb, resourceName string, resource schema.GroupResource, allowed bool) error {
+	review := &authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Group:     resource.Group,
+				Verb:      verb,
+				Resource:  resource.Resource,
+				Namespace: namespace,
+				Name:      resourceName,
+			},
+			User: user,
+		},
+	}
+
+	err := wait.PollUntil(policyCachePollInterval, func() (bool, error) {
+		response, err := c.SubjectAccessReviews().Create(review)
+		if apierrors.IsNotFound(err) {
+			logf("SubjectAccessReview endpoint is missing")
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return response.Status.Allowed == allowed, nil
+	}, ctx.Done())
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("waiting for authorization update for %q: %v (context: %v)", user, err, ctx.Err())
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// StressResult tallies the outcomes of a StressAuthorization run.
+type StressResult struct {
+	Total  int64
+	Denied int64
+	Errors int64
+}
+
+// StressAuthorization hammers the SubjectAccessReview endpoint for duration
+// from concurrency goroutines, goroutine i evaluating attrs as
+// users[i%len(users)]. It is intended for tests of authorizer cache correctness
+// under contention, not correctness of any single decision: callers should
+// inspect the returned StressResult's Denied/Errors rates rather than any
+// individual response.
+func StressAuthorization(c v1beta1authorization.SubjectAccessReviewsGetter, users []string, attrs authorizationv1beta1.ResourceAttributes, concurrency int, duration time.Duration) StressResult {
+	var result StressResult
+	deadline := time.Now().Add(duration)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		user := users[i%len(users)]
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				review := &authorizationv1beta1.SubjectAccessReview{
+					Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+						ResourceAttributes: attrs.DeepCopy(),
+						User:               user,
+					},
+				}
+				atomic.AddInt64(&result.Total, 1)
+				response, err := c.SubjectAccessReviews().Create(review)
+				if err != nil {
+					atomic.AddInt64(&result.Errors, 1)
+					continue
+				}
+				if !response.Status.Allowed {
+					atomic.AddInt64(&result.Denied, 1)
+				}
+			}
+		}(user)
+	}
+	wg.Wait()
+
+	return result
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// SuiteSummary tracks how many RBAC objects and identities this package's
+// helpers have created and cleaned up over the life of a test binary, so
+// suite owners can spot suites that litter clusters with RBAC objects.
+type SuiteSummary struct {
+	Created int64
+	Cleaned int64
+}
+
+// Leaked returns the number of objects created but never observed cleaned.
+func (s SuiteSummary) Leaked() int64 {
+	if s.Created > s.Cleaned {
+		return s.Created - s.Cleaned
+	}
+	return 0
+}
+
+var (
+	objectsCreated int64
+	objectsCleaned int64
+)
+
+func recordObjectCreated() {
+	atomic.AddInt64(&objectsCreated, 1)
+}
+
+func recordObjectCleaned() {
+	atomic.AddInt64(&objectsCleaned, 1)
+}
+
+// GetSuiteSummary returns the current tally of created/cleaned auth objects.
+func GetSuiteSummary() SuiteSummary {
+	return SuiteSummary{
+		Created: atomic.LoadInt64(&objectsCreated),
+		Cleaned: atomic.LoadInt64(&objectsCleaned),
+	}
+}
+
+// LogSuiteSummary logs the current SuiteSummary, and if leaked > 0 also
+// returns its JSON encoding so callers (typically an AfterSuite) can attach
+// it as a machine-readable artifact.
+func LogSuiteSummary() string {
+	summary := GetSuiteSummary()
+	logf("auth helper suite summary: created=%d cleaned=%d leaked=%d", summary.Created, summary.Cleaned, summary.Leaked())
+	if summary.Leaked() == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		logf("failed to marshal auth helper suite summary: %v", err)
+		return ""
+	}
+	return string(data)
+}
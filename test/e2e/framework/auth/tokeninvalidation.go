@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// WaitForTokenInvalidated polls with client, a credential that was valid
+// when issued, until the apiserver starts rejecting it with 401 Unauthorized
+// -- the authn counterpart to this package's authz propagation waits (e.g.
+// WaitForAuthorizationUpdate), for covering bound service account token
+// invalidation after the pod, Secret, or ServiceAccount it's scoped to is
+// deleted.
+func WaitForTokenInvalidated(client clientset.Interface) error {
+	err := wait.Poll(policyCachePollInterval, policyCachePollTimeout, func() (bool, error) {
+		_, err := client.CoreV1().ServiceAccounts(metav1.NamespaceDefault).List(metav1.ListOptions{})
+		if apierrors.IsUnauthorized(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for credential to be invalidated: %v", err)
+	}
+	return nil
+}
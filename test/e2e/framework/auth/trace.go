@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1beta1rbac "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+)
+
+// Annotation keys stamped onto every RBAC object this package creates, so
+// that a stray binding found on a shared test cluster can be traced back to
+// the spec and suite run that left it there.
+const (
+	traceSpecAnnotation      = "e2e.kubernetes.io/spec"
+	traceRunIDAnnotation     = "e2e.kubernetes.io/suite-run-id"
+	traceTimestampAnnotation = "e2e.kubernetes.io/created-at"
+)
+
+// suiteRunID identifies this test binary invocation; it is stable for every
+// object created during the run and lets post-mortem queries group objects
+// by run without relying on clock precision alone.
+var suiteRunID = fmt.Sprintf("run-%d", time.Now().UnixNano())
+
+// traceAnnotations returns the annotation set BindClusterRole and friends
+// stamp onto objects they create: the full ginkgo spec text (if a spec is
+// currently running), the suite run ID, and a creation timestamp.
+func traceAnnotations() map[string]string {
+	annotations := map[string]string{
+		traceRunIDAnnotation:     suiteRunID,
+		traceTimestampAnnotation: time.Now().Format(time.RFC3339),
+	}
+	if desc := ginkgo.CurrentGinkgoTestDescription().FullTestText; desc != "" {
+		annotations[traceSpecAnnotation] = desc
+	}
+	return annotations
+}
+
+// ListClusterRoleBindingsForSpec returns the ClusterRoleBindings whose trace
+// annotation records they were created by the named spec, for post-mortem
+// attribution on a shared cluster.
+func ListClusterRoleBindingsForSpec(c v1beta1rbac.ClusterRoleBindingsGetter, specFullText string) ([]string, error) {
+	bindings, err := c.ClusterRoleBindings().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, binding := range bindings.Items {
+		if binding.Annotations[traceSpecAnnotation] == specFullText {
+			names = append(names, binding.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/utils/trace"
+)
+
+// tracingEnabled gates whether traceOp emits per-call trace logging for
+// authorization waits and identity creation. go.opentelemetry.io isn't
+// vendored in this tree, so this can't emit real OTel spans for the CI
+// observability stack to ingest; it uses the k8s.io/utils/trace helper
+// apiserver itself uses for the same "where did the time go" purpose,
+// logged via klog instead of exported as a span. Off by default so normal
+// runs aren't spammed with per-SAR trace logs.
+var tracingEnabled = false
+
+// EnableTracing turns tracing on or off for the remainder of the process.
+func EnableTracing(enabled bool) {
+	tracingEnabled = enabled
+}
+
+// traceOp starts a trace named op (tagged with attrs, rendered inline since
+// k8s.io/utils/trace.Trace has no field/tag API) if tracing is enabled, and
+// returns a func that logs it if it ran longer than policyCachePollInterval. Both are
+// no-ops when tracing is disabled.
+func traceOp(op string, attrs ...interface{}) func() {
+	if !tracingEnabled {
+		return func() {}
+	}
+	t := trace.New(fmt.Sprintf("auth: %s %v", op, attrs))
+	return func() { t.LogIfLong(policyCachePollInterval) }
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/onsi/ginkgo"
+)
+
+var userAgentSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_.:/-]+`)
+
+// IdentityUserAgent builds a User-Agent tag for a client acting as identity
+// (a username, service account, or certificate CN), embedding the current
+// ginkgo spec's name so apiserver and audit logs can be correlated back to
+// the test that issued the request -- otherwise every impersonated/SA/cert
+// client this package builds shows up in logs indistinguishable from the
+// default "e2e.test" User-Agent. Callers that need to grep for their own
+// requests should search logs for the exact string this returns.
+func IdentityUserAgent(identity string) string {
+	spec := ginkgo.CurrentGinkgoTestDescription().FullTestText
+	return fmt.Sprintf("e2e-auth-test/%s/%s", userAgentSanitizer.ReplaceAllString(spec, "_"), userAgentSanitizer.ReplaceAllString(identity, "_"))
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// ReadOnlyVerbs returns the verbs a read-only role is expected to grant.
+// "watch" is easy to forget alongside "get"/"list"; centralizing the list
+// here means suites testing read-only access don't have to remember it.
+func ReadOnlyVerbs() []string {
+	return []string{"get", "list", "watch"}
+}
+
+// WriteVerbs returns the verbs a full-access role is expected to grant,
+// including deletecollection and patch, which are easy to leave out of a
+// hand-written verb list when testing "full" access.
+func WriteVerbs() []string {
+	return []string{"create", "update", "patch", "delete", "deletecollection"}
+}
+
+// AssertVerbMatrix checks, in one call, that user is allowed to perform
+// every verb in allowed and denied every verb in denied against resource in
+// namespace, covering combinations (like deletecollection or a custom verb)
+// that a hand-rolled loop over a couple of verbs tends to miss.
+func AssertVerbMatrix(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace string, resource schema.GroupResource, allowed, denied []string) error {
+	for _, verb := range allowed {
+		if err := WaitForAuthorizationUpdate(c, user, namespace, verb, resource, true); err != nil {
+			return fmt.Errorf("expected %q to be allowed to %s %s: %v", user, verb, resource, err)
+		}
+	}
+	for _, verb := range denied {
+		if err := WaitForAuthorizationUpdate(c, user, namespace, verb, resource, false); err != nil {
+			return fmt.Errorf("expected %q to be denied %s %s: %v", user, verb, resource, err)
+		}
+	}
+	return nil
+}
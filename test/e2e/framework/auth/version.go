@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// WaitForAuthorizationUpdateWithVersion behaves like WaitForAuthorizationUpdate,
+// but additionally sets ResourceAttributes.Version to resource.Version, for
+// asserting that RBAC (which is version-agnostic by design) and any
+// configured authorization webhook agree on a decision regardless of which
+// API version the request names, catching a webhook that incorrectly
+// differentiates by version.
+func WaitForAuthorizationUpdateWithVersion(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb string, resource schema.GroupVersionResource, allowed bool) error {
+	return WaitForNamedAuthorizationUpdateWithVersion(c, user, namespace, verb, "", resource, allowed)
+}
+
+// WaitForNamedAuthorizationUpdateWithVersion is the named-resource
+// counterpart of WaitForAuthorizationUpdateWithVersion.
+func WaitForNamedAuthorizationUpdateWithVersion(c v1beta1authorization.SubjectAccessReviewsGetter, user, namespace, verb, resourceName string, resource schema.GroupVersionResource, allowed bool) error {
+	review := &authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Group:     resource.Group,
+				Version:   resource.Version,
+				Verb:      verb,
+				Resource:  resource.Resource,
+				Namespace: namespace,
+				Name:      resourceName,
+			},
+			User: user,
+		},
+	}
+
+	start := time.Now()
+	var lastResponse *authorizationv1beta1.SubjectAccessReview
+	var lastErr error
+	polls := 0
+	err := wait.Poll(policyCachePollInterval, cacheAwarePollTimeout(allowed), func() (bool, error) {
+		polls++
+		response, err := c.SubjectAccessReviews().Create(review)
+		if apierrors.IsNotFound(err) {
+			logf("SubjectAccessReview endpoint is missing")
+			time.Sleep(1 * time.Second)
+			return true, nil
+		}
+		if err != nil {
+			lastErr = err
+			return false, err
+		}
+		lastResponse = response
+		if response.Status.Allowed != allowed {
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return reportAuthorizationFailure(review.Spec, allowed, lastResponse, time.Since(start), polls, lastErr)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// AssertWatchAuthorizationUpdate opens a watch on pods in namespace using
+// client (which must already authenticate as the identity under test) and
+// asserts that opening it succeeds or is denied according to allowed. This
+// complements the SubjectAccessReview-based Wait* helpers in this package: a
+// SubjectAccessReview only predicts what the authorizer would decide, it
+// does not exercise the watch verb's actual, long-lived request path.
+func AssertWatchAuthorizationUpdate(client clientset.Interface, namespace string, allowed bool) error {
+	watcher, err := client.CoreV1().Pods(namespace).Watch(metav1.ListOptions{})
+	if allowed {
+		if err != nil {
+			return fmt.Errorf("expected watch on pods in %q to be authorized, got: %v", namespace, err)
+		}
+		watcher.Stop()
+		return nil
+	}
+	if err == nil {
+		watcher.Stop()
+		return fmt.Errorf("expected watch on pods in %q to be denied, but it opened successfully", namespace)
+	}
+	if !apierrors.IsForbidden(err) {
+		return fmt.Errorf("expected a Forbidden error opening watch on pods in %q, got: %v", namespace, err)
+	}
+	return nil
+}
+
+// AssertWatchSurvivesRevocation opens a watch on pods in namespace as
+// client, calls revoke (e.g. to delete the RoleBinding that granted the
+// watch), and asserts the watch's event channel is still open window later.
+//
+// A stock apiserver authorizes a watch once, at connection open; revoking a
+// binding afterwards does not retroactively tear down an already-streaming
+// watch, so this helper asserts survival, not termination, by design. It
+// exists to make that behavior explicit and regression-tested, since a
+// SubjectAccessReview against the (now revoked) identity would report
+// denied while the live watch keeps delivering events regardless.
+func AssertWatchSurvivesRevocation(client clientset.Interface, namespace string, revoke func() error, window time.Duration) error {
+	watcher, err := client.CoreV1().Pods(namespace).Watch(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("opening watch on pods in %q before revocation: %v", namespace, err)
+	}
+	defer watcher.Stop()
+
+	if err := revoke(); err != nil {
+		return fmt.Errorf("revoking access mid-watch: %v", err)
+	}
+
+	deadline := time.After(window)
+	for {
+		select {
+		case _, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch on pods in %q was closed within %v of revocation; expected it to keep streaming since a stock apiserver does not tear down established watches on RBAC changes", namespace, window)
+			}
+		case <-deadline:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// TokenAuthenticatorWebhook is an in-process TokenReview backend, for
+// clusters whose apiserver is started with
+// --authentication-token-webhook-config-file pointing at it. Programming the
+// apiserver to point at Server().URL is out of scope for this package (it's
+// a cluster bring-up concern, not a client one); this exists to let suites
+// that already run such a cluster program token->identity mappings at
+// runtime and issue requests bearing those tokens.
+type TokenAuthenticatorWebhook struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	tokens map[string]authenticationv1.UserInfo
+}
+
+// NewTokenAuthenticatorWebhook starts an httptest server implementing the
+// authentication.k8s.io TokenReview webhook contract, with no tokens
+// authenticated yet.
+func NewTokenAuthenticatorWebhook() *TokenAuthenticatorWebhook {
+	w := &TokenAuthenticatorWebhook{tokens: map[string]authenticationv1.UserInfo{}}
+	w.server = httptest.NewServer(http.HandlerFunc(w.serveTokenReview))
+	return w
+}
+
+// URL is the webhook endpoint to configure the apiserver's
+// --authentication-token-webhook-config-file kubeconfig against.
+func (w *TokenAuthenticatorWebhook) URL() string {
+	return w.server.URL
+}
+
+// Close shuts down the webhook server.
+func (w *TokenAuthenticatorWebhook) Close() {
+	w.server.Close()
+}
+
+// SetToken programs token to authenticate as user. Safe for concurrent use.
+func (w *TokenAuthenticatorWebhook) SetToken(token string, user authenticationv1.UserInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tokens[token] = user
+}
+
+// RevokeToken removes a previously programmed token, so subsequent
+// TokenReviews for it are reported unauthenticated.
+func (w *TokenAuthenticatorWebhook) RevokeToken(token string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.tokens, token)
+}
+
+func (w *TokenAuthenticatorWebhook) serveTokenReview(rw http.ResponseWriter, req *http.Request) {
+	var review authenticationv1.TokenReview
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.mu.Lock()
+	user, ok := w.tokens[review.Spec.Token]
+	w.mu.Unlock()
+
+	review.Status = authenticationv1.TokenReviewStatus{Authenticated: ok, User: user}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(review)
+}
+
+// NewBearerTokenClient returns a clientset that authenticates using token as
+// a bearer token, exercising whatever authenticator (webhook or otherwise)
+// the apiserver has configured to accept it.
+func NewBearerTokenClient(base *restclient.Config, token string) (clientset.Interface, error) {
+	config := restclient.AnonymousClientConfig(base)
+	config.BearerToken = token
+	config.UserAgent = IdentityUserAgent(fmt.Sprintf("bearer-token:%s", token))
+	return clientset.NewForConfig(config)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	v1beta1authorization "k8s.io/client-go/kubernetes/typed/authorization/v1beta1"
+)
+
+// AuthorizationWebhook is an in-process SubjectAccessReview backend, for
+// clusters whose apiserver is started with an authorization webhook pointed
+// at it (--authorization-webhook-config-file). Unlike TokenAuthenticatorWebhook,
+// this also lets a test force the backend unreachable or slow, to assert on
+// the apiserver's webhook-authorizer FailurePolicy handling and request
+// timeout rather than only its happy-path decisions.
+type AuthorizationWebhook struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	decisions   map[string]authorizationv1beta1.SubjectAccessReviewStatus
+	unreachable bool
+	latency     time.Duration
+}
+
+// RequireWebhookAuthzConfigurable returns an error if Capabilities reports
+// the current provider does not let a test point the apiserver's
+// authorization webhook at an AuthorizationWebhook's URL, so a caller can
+// skip gracefully instead of starting a server nothing will ever reach.
+func RequireWebhookAuthzConfigurable() error {
+	if !Capabilities().CanConfigureWebhookAuthz {
+		return fmt.Errorf("the current provider does not support configuring an authorization webhook")
+	}
+	return nil
+}
+
+// NewAuthorizationWebhook starts an httptest server implementing the
+// authorization.k8s.io SubjectAccessReview webhook contract, denying every
+// request until SetDecision programs one.
+func NewAuthorizationWebhook() *AuthorizationWebhook {
+	w := &AuthorizationWebhook{decisions: map[string]authorizationv1beta1.SubjectAccessReviewStatus{}}
+	w.server = httptest.NewServer(http.HandlerFunc(w.serveSubjectAccessReview))
+	return w
+}
+
+// URL is the webhook endpoint to configure the apiserver's
+// --authorization-webhook-config-file kubeconfig against.
+func (w *AuthorizationWebhook) URL() string {
+	return w.server.URL
+}
+
+// Close shuts down the webhook server.
+func (w *AuthorizationWebhook) Close() {
+	w.server.Close()
+}
+
+// SetDecision programs the webhook to answer every SubjectAccessReview for
+// user with status. Safe for concurrent use.
+func (w *AuthorizationWebhook) SetDecision(user string, status authorizationv1beta1.SubjectAccessReviewStatus) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.decisions[user] = status
+}
+
+// MakeUnreachable causes every subsequent request to hang until the
+// apiserver's webhook client gives up, simulating a network partition. This
+// is the closest approximation to a severed connection httptest allows
+// without tearing down and racing to rebind the listening socket; call
+// Reachable to undo it.
+func (w *AuthorizationWebhook) MakeUnreachable() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.unreachable = true
+}
+
+// Reachable undoes MakeUnreachable and SetLatency, returning the webhook to
+// answering immediately.
+func (w *AuthorizationWebhook) Reachable() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.unreachable = false
+	w.latency = 0
+}
+
+// SetLatency delays every response by d, for asserting on the apiserver's
+// webhook request timeout behavior rather than its unreachable-backend
+// handling.
+func (w *AuthorizationWebhook) SetLatency(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.latency = d
+}
+
+func (w *AuthorizationWebhook) serveSubjectAccessReview(rw http.ResponseWriter, req *http.Request) {
+	w.mu.Lock()
+	unreachable := w.unreachable
+	latency := w.latency
+	w.mu.Unlock()
+
+	if unreachable {
+		<-req.Context().Done()
+		return
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	var review authorizationv1beta1.SubjectAccessReview
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.mu.Lock()
+	status := w.decisions[review.Spec.User]
+	w.mu.Unlock()
+	review.Status = status
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(review)
+}
+
+// AssertDeniedWhileUnreachable makes w unreachable for the duration of the
+// check and asserts user is denied verb on resource, the standard behavior
+// of an apiserver started with the webhook authorizer's default Deny
+// FailurePolicy whenever a request can't reach the webhook at all.
+func AssertDeniedWhileUnreachable(c v1beta1authorization.SubjectAccessReviewsGetter, w *AuthorizationWebhook, user, namespace, verb string, resource schema.GroupResource) error {
+	w.MakeUnreachable()
+	defer w.Reachable()
+	return WaitForAuthorizationUpdate(c, user, namespace, verb, resource, false)
+}
+
+// AssertCachedDecisionSurvivesOutage sets an "allowed" decision, waits for it
+// to propagate while the webhook is reachable, then makes the webhook
+// unreachable and asserts the previously cached decision is still honored
+// rather than immediately flipping to denied, verifying the apiserver serves
+// it from its authorizer cache instead of the now-unreachable backend. It
+// checks only once, so it says nothing about behavior past
+// authorizedDecisionCacheTTL (see SetAuthorizerCacheTTLs); a caller wanting
+// to observe the cache actually expire should wait that long and then use
+// AssertDeniedWhileUnreachable.
+func AssertCachedDecisionSurvivesOutage(c v1beta1authorization.SubjectAccessReviewsGetter, w *AuthorizationWebhook, user, namespace, verb string, resource schema.GroupResource) error {
+	w.SetDecision(user, authorizationv1beta1.SubjectAccessReviewStatus{Allowed: true})
+	if err := WaitForAuthorizationUpdate(c, user, namespace, verb, resource, true); err != nil {
+		return fmt.Errorf("waiting for initial allow decision to propagate: %v", err)
+	}
+
+	w.MakeUnreachable()
+	defer w.Reachable()
+
+	review, err := c.SubjectAccessReviews().Create(&authorizationv1beta1.SubjectAccessReview{
+		Spec: authorizationv1beta1.SubjectAccessReviewSpec{
+			User: user,
+			ResourceAttributes: &authorizationv1beta1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     resource.Group,
+				Resource:  resource.Resource,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("checking cached decision while webhook unreachable: %v", err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("cached allow decision for %s was not honored once the webhook became unreachable: %+v", user, review.Status)
+	}
+	return nil
+}
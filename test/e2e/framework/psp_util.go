@@ -139,7 +139,7 @@ func createPrivilegedPSPBinding(f *Framework, namespace string) {
 	if auth.IsRBACEnabled(f.ClientSet.RbacV1beta1()) {
 		ginkgo.By(fmt.Sprintf("Binding the %s PodSecurityPolicy to the default service account in %s",
 			podSecurityPolicyPrivileged, namespace))
-		err := auth.BindClusterRoleInNamespace(f.ClientSet.RbacV1beta1(),
+		_, err := auth.BindClusterRoleInNamespace(f.ClientSet.RbacV1beta1(),
 			podSecurityPolicyPrivileged,
 			namespace,
 			rbacv1beta1.Subject{
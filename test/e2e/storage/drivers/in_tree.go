@@ -156,7 +156,9 @@ func (n *nfsDriver) PrepareTest(f *framework.Framework) (*testsuites.PerTestConf
 
 	// TODO(mkimuram): cluster-admin gives too much right but system:persistent-volume-provisioner
 	// is not enough. We should create new clusterrole for testing.
-	err := auth.BindClusterRole(cs.RbacV1beta1(), "cluster-admin", ns.Name,
+	auth.AllowPrivilegedBindings(true)
+	defer auth.AllowPrivilegedBindings(false)
+	_, err := auth.BindClusterRole(cs.RbacV1beta1(), "cluster-admin", ns.Name,
 		rbacv1beta1.Subject{Kind: rbacv1beta1.ServiceAccountKind, Namespace: ns.Name, Name: "default"})
 	framework.ExpectNoError(err)
 
@@ -1498,9 +1500,9 @@ type awsDriver struct {
 var _ testsuites.TestDriver = &awsDriver{}
 
 // TODO: Fix authorization error in attach operation and uncomment below
-//var _ testsuites.PreprovisionedVolumeTestDriver = &awsDriver{}
-//var _ testsuites.InlineVolumeTestDriver = &awsDriver{}
-//var _ testsuites.PreprovisionedPVTestDriver = &awsDriver{}
+// var _ testsuites.PreprovisionedVolumeTestDriver = &awsDriver{}
+// var _ testsuites.InlineVolumeTestDriver = &awsDriver{}
+// var _ testsuites.PreprovisionedPVTestDriver = &awsDriver{}
 var _ testsuites.DynamicPVTestDriver = &awsDriver{}
 
 // InitAwsDriver returns awsDriver that implements TestDriver interface
@@ -712,7 +712,7 @@ var _ = utils.SIGDescribe("Dynamic Provisioning", func() {
 				Name:      serviceAccountName,
 			}
 
-			err := auth.BindClusterRole(c.RbacV1beta1(), "system:persistent-volume-provisioner", ns, subject)
+			_, err := auth.BindClusterRole(c.RbacV1beta1(), "system:persistent-volume-provisioner", ns, subject)
 			framework.ExpectNoError(err)
 
 			roleName := "leader-locking-nfs-provisioner"
@@ -728,7 +728,7 @@ var _ = utils.SIGDescribe("Dynamic Provisioning", func() {
 			})
 			framework.ExpectNoError(err, "Failed to create leader-locking role")
 
-			err = auth.BindRoleInNamespace(c.RbacV1beta1(), roleName, ns, subject)
+			_, err = auth.BindRoleInNamespace(c.RbacV1beta1(), roleName, ns, subject)
 			framework.ExpectNoError(err)
 
 			err = auth.WaitForAuthorizationUpdate(c.AuthorizationV1beta1(),